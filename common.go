@@ -0,0 +1,35 @@
+package lts
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+)
+
+// maxEmbeddedJSONDepth limits how many JSON string layers are unwrapped before the payload is decoded.
+const maxEmbeddedJSONDepth = 3
+
+// unmarshalEmbeddedJSON decodes LTS responses which carry a JSON document encoded as a JSON string,
+// e.g. "{\"id\":\"...\"}". The string layers are unwrapped with the JSON decoder itself, so escaped quotes,
+// backslashes, unicode escapes and JSON inside field values keep their meaning. Plain JSON documents are
+// decoded as they are.
+func unmarshalEmbeddedJSON(data []byte, v interface{}) error {
+	payload := bytes.TrimSpace(data)
+	for i := 0; len(payload) > 0 && payload[0] == '"'; i++ {
+		if i == maxEmbeddedJSONDepth {
+			return fmt.Errorf("the payload is encoded more than %d times", maxEmbeddedJSONDepth)
+		}
+		var inner string
+		if err := json.Unmarshal(payload, &inner); err != nil {
+			return fmt.Errorf("error unwrapping the JSON string: %s", err)
+		}
+		payload = bytes.TrimSpace([]byte(inner))
+	}
+	if len(payload) == 0 {
+		return fmt.Errorf("the payload is empty")
+	}
+	if err := json.Unmarshal(payload, v); err != nil {
+		return fmt.Errorf("error decoding the JSON document: %s", err)
+	}
+	return nil
+}
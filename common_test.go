@@ -0,0 +1,101 @@
+package lts
+
+import (
+	"reflect"
+	"testing"
+)
+
+type testStructTemplate struct {
+	Id         string `json:"id"`
+	DemoLog    string `json:"demoLog"`
+	LogGroupId string `json:"logGroupId"`
+}
+
+func TestUnmarshalEmbeddedJSON(t *testing.T) {
+	cases := []struct {
+		name    string
+		body    string
+		want    testStructTemplate
+		wantErr bool
+	}{
+		{
+			name: "plain object",
+			body: `{"id":"8f7e1c2a","demoLog":"GET /apm 404","logGroupId":"b1c2"}`,
+			want: testStructTemplate{Id: "8f7e1c2a", DemoLog: "GET /apm 404", LogGroupId: "b1c2"},
+		},
+		{
+			name: "double encoded object",
+			body: `"{\"id\":\"8f7e1c2a\",\"demoLog\":\"GET /apm 404\",\"logGroupId\":\"b1c2\"}"`,
+			want: testStructTemplate{Id: "8f7e1c2a", DemoLog: "GET /apm 404", LogGroupId: "b1c2"},
+		},
+		{
+			name: "quotes in the sample log",
+			body: `"{\"id\":\"8f7e1c2a\",\"demoLog\":\"127.0.0.1 \\\"GET /apm HTTP/1.1\\\" 404\"}"`,
+			want: testStructTemplate{Id: "8f7e1c2a", DemoLog: `127.0.0.1 "GET /apm HTTP/1.1" 404`},
+		},
+		{
+			name: "backslashes in the sample log",
+			body: `"{\"id\":\"8f7e1c2a\",\"demoLog\":\"C:\\\\logs\\\\app.log \\\\d+\"}"`,
+			want: testStructTemplate{Id: "8f7e1c2a", DemoLog: `C:\logs\app.log \d+`},
+		},
+		{
+			name: "unicode escapes",
+			body: `"{\"id\":\"8f7e1c2a\",\"demoLog\":\"user=\\u5f20\\u4e09 \\u003cadmin\\u003e\"}"`,
+			want: testStructTemplate{Id: "8f7e1c2a", DemoLog: "user=张三 <admin>"},
+		},
+		{
+			name: "embedded JSON sample log",
+			body: `"{\"id\":\"8f7e1c2a\",\"demoLog\":\"{\\\"level\\\":\\\"info\\\",\\\"msg\\\":\\\"a \\\\\\\"b\\\\\\\"\\\"}\"}"`,
+			want: testStructTemplate{Id: "8f7e1c2a", DemoLog: `{"level":"info","msg":"a \"b\""}`},
+		},
+		{
+			name: "surrounding whitespace",
+			body: " \n\"{\\\"id\\\":\\\"8f7e1c2a\\\"}\"\n",
+			want: testStructTemplate{Id: "8f7e1c2a"},
+		},
+		{
+			name:    "empty body",
+			body:    "",
+			wantErr: true,
+		},
+		{
+			name:    "empty string",
+			body:    `""`,
+			wantErr: true,
+		},
+		{
+			name:    "truncated string",
+			body:    `"{\"id\":\"8f7e1c2a\"`,
+			wantErr: true,
+		},
+		{
+			name:    "string without a document",
+			body:    `"internal error"`,
+			wantErr: true,
+		},
+		{
+			name:    "too many layers",
+			body:    `"\"\\\"\\\\\\\"{}\\\\\\\"\\\"\""`,
+			wantErr: true,
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			var got testStructTemplate
+			err := unmarshalEmbeddedJSON([]byte(tc.body), &got)
+			if tc.wantErr {
+				if err == nil {
+					t.Fatalf("expected an error, got %+v", got)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error: %s", err)
+			}
+			if !reflect.DeepEqual(got, tc.want) {
+				t.Fatalf("expected %+v, got %+v", tc.want, got)
+			}
+		})
+	}
+}
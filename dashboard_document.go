@@ -0,0 +1,247 @@
+package lts
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"regexp"
+	"sort"
+	"strings"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/huaweicloud/terraform-provider-huaweicloud/huaweicloud/config"
+	"github.com/huaweicloud/terraform-provider-huaweicloud/huaweicloud/services/internal/entity"
+	"github.com/huaweicloud/terraform-provider-huaweicloud/huaweicloud/services/internal/httpclient_go"
+)
+
+// The variables which replace the log group and log stream IDs of a dashboard document. Further log groups and
+// streams get a numeric suffix, e.g. ${log_group_id_2}.
+const (
+	logGroupIdVariable  = "log_group_id"
+	logStreamIdVariable = "log_stream_id"
+)
+
+var dashboardVariableRegexp = regexp.MustCompile(`^\$\{([A-Za-z0-9_]+)\}$`)
+
+// dashboardInfo holds the dashboard attributes of a document, the filters have the shape of the filter blocks.
+type dashboardInfo struct {
+	Title     string            `json:"title"`
+	GroupName string            `json:"group_name,omitempty"`
+	Filters   []dashBoardFilter `json:"filters,omitempty"`
+}
+
+// dashboardInfoRequest is dashboardInfo in the API shape, which keeps each filter as a JSON string.
+type dashboardInfoRequest struct {
+	Title     string   `json:"title"`
+	GroupName string   `json:"group_name,omitempty"`
+	Filters   []string `json:"filters,omitempty"`
+}
+
+// dashboardDocument is a dashboard with its charts as exported by the lts_dashboard_json data source.
+type dashboardDocument struct {
+	dashboardInfo
+	Charts []dashboardChart `json:"charts"`
+}
+
+// decodeDashboardDocument decodes a dashboard document. The keys which the document does not model are rejected,
+// they would be dropped from the dashboard otherwise.
+func decodeDashboardDocument(document string) (*dashboardDocument, error) {
+	doc := dashboardDocument{}
+	decoder := json.NewDecoder(strings.NewReader(document))
+	decoder.DisallowUnknownFields()
+	if err := decoder.Decode(&doc); err != nil {
+		return nil, err
+	}
+	if _, err := decoder.Token(); err != io.EOF {
+		return nil, fmt.Errorf("the document contains data after the dashboard")
+	}
+	return &doc, nil
+}
+
+// normalizeDashboardJson decodes a dashboard document and encodes it again without the server assigned chart IDs,
+// so documents which only differ in key order, omitted empty values or server fields are equal. The documents are
+// validated by decodeDashboardDocument, so no configured key is lost here.
+func normalizeDashboardJson(document string) (string, error) {
+	doc := dashboardDocument{}
+	if err := json.Unmarshal([]byte(document), &doc); err != nil {
+		return "", err
+	}
+	for i := range doc.Charts {
+		doc.Charts[i].Id = ""
+		doc.Charts[i].DashboardId = ""
+	}
+	buf := &bytes.Buffer{}
+	encoder := json.NewEncoder(buf)
+	encoder.SetEscapeHTML(false)
+	if err := encoder.Encode(doc); err != nil {
+		return "", err
+	}
+	return strings.TrimSuffix(buf.String(), "\n"), nil
+}
+
+func suppressDashboardJsonDiffs(_, old, new string, _ *schema.ResourceData) bool {
+	oldJson, err := normalizeDashboardJson(old)
+	if err != nil {
+		return false
+	}
+	newJson, err := normalizeDashboardJson(new)
+	if err != nil {
+		return false
+	}
+	return oldJson == newJson
+}
+
+func validateDashboardJson(v interface{}, k string) ([]string, []error) {
+	document, err := decodeDashboardDocument(v.(string))
+	if err != nil {
+		return nil, []error{fmt.Errorf("%q is not a valid dashboard document: %s", k, err)}
+	}
+	var errs []error
+	for i, filter := range document.Filters {
+		if !containsString(dashBoardFilterTypes, filter.Type) {
+			errs = append(errs, fmt.Errorf("%q: filter %d has an invalid type %q, must be one of %v", k, i,
+				filter.Type, dashBoardFilterTypes))
+		}
+		if err := checkDashBoardFilter(filter); err != nil {
+			errs = append(errs, fmt.Errorf("%q: filter %d: %s", k, i, err))
+		}
+	}
+	for i, chart := range document.Charts {
+		if !containsString(dashboardChartTypes, chart.Type) {
+			errs = append(errs, fmt.Errorf("%q: chart %d has an invalid type %q, must be one of %v", k, i,
+				chart.Type, dashboardChartTypes))
+		}
+		if chart.Sql == "" {
+			errs = append(errs, fmt.Errorf("%q: chart %d has no sql", k, i))
+		}
+	}
+	return nil, errs
+}
+
+func buildDashboardInfoRequest(info dashboardInfo) (dashboardInfoRequest, error) {
+	filters, err := encodeDashBoardFilters(info.Filters)
+	if err != nil {
+		return dashboardInfoRequest{}, err
+	}
+	return dashboardInfoRequest{
+		Title:     info.Title,
+		GroupName: info.GroupName,
+		Filters:   filters,
+	}, nil
+}
+
+// getDashBoard queries a dashboard by its ID, a nil dashboard is returned if it does not exist.
+func getDashBoard(cfg *config.Config, region, dashBoardId string) (*entity.DashBoard, diag.Diagnostics) {
+	rlt := entity.ReadDashBoardResp{}
+	statusCode, diags := doLtsRequest(cfg, region, httpclient_go.MethodGet, "/dashboards?id="+dashBoardId, nil, &rlt)
+	if diags != nil {
+		return nil, diags
+	}
+	if statusCode == 404 || len(rlt.Results) == 0 {
+		return nil, nil
+	}
+	return &rlt.Results[0], nil
+}
+
+// renderDashboardDocument builds the document of a dashboard, its charts keep the order of the dashboard. A filter
+// which is not valid JSON can not be rendered, it is reported instead of being dropped from the document.
+func renderDashboardDocument(cfg *config.Config, region string,
+	dashBoard *entity.DashBoard) (*dashboardDocument, diag.Diagnostics) {
+	filters, invalid := decodeDashBoardFilters(dashBoard.Filters)
+	if len(invalid) > 0 {
+		return nil, diag.Errorf("error rendering LtsDashBoard %s: the filter %s is not valid JSON", dashBoard.Id,
+			invalid[0])
+	}
+	document := dashboardDocument{
+		dashboardInfo: dashboardInfo{
+			Title:     dashBoard.Title,
+			GroupName: dashBoard.GroupName,
+			Filters:   filters,
+		},
+		Charts: make([]dashboardChart, 0, len(dashBoard.Charts)),
+	}
+	for _, chartId := range dashBoard.Charts {
+		chart, diags := getDashboardChart(cfg, region, chartId)
+		if diags != nil {
+			return nil, diags
+		}
+		if chart == nil {
+			continue
+		}
+		chart.Id = ""
+		chart.DashboardId = ""
+		document.Charts = append(document.Charts, *chart)
+	}
+	return &document, nil
+}
+
+// templateDashboardDocument replaces the log group and log stream IDs of the charts by variables and returns the
+// IDs by variable name. The IDs of bound keep their variable names, the other IDs get new ones.
+func templateDashboardDocument(document *dashboardDocument, bound map[string]string) map[string]string {
+	variables := make(map[string]string)
+	names := make(map[string]string)
+	boundNames := make([]string, 0, len(bound))
+	for name := range bound {
+		boundNames = append(boundNames, name)
+	}
+	sort.Strings(boundNames)
+	for _, name := range boundNames {
+		id := bound[name]
+		if id == "" {
+			continue
+		}
+		variables[name] = id
+		if _, ok := names[id]; !ok {
+			names[id] = name
+		}
+	}
+	bind := func(prefix, id string) string {
+		if name, ok := names[id]; ok {
+			return name
+		}
+		name := prefix
+		for i := 2; variables[name] != ""; i++ {
+			name = fmt.Sprintf("%s_%d", prefix, i)
+		}
+		variables[name] = id
+		names[id] = name
+		return name
+	}
+	for i := range document.Charts {
+		chart := &document.Charts[i]
+		if chart.LogGroupId != "" {
+			chart.LogGroupId = "${" + bind(logGroupIdVariable, chart.LogGroupId) + "}"
+		}
+		if chart.LogStreamId != "" {
+			chart.LogStreamId = "${" + bind(logStreamIdVariable, chart.LogStreamId) + "}"
+		}
+	}
+	return variables
+}
+
+// expandDashboardDocument replaces the variables of the charts by the given IDs, the charts may also use plain IDs.
+func expandDashboardDocument(document *dashboardDocument, variables map[string]string) error {
+	expand := func(value string) (string, error) {
+		match := dashboardVariableRegexp.FindStringSubmatch(value)
+		if match == nil {
+			return value, nil
+		}
+		if id, ok := variables[match[1]]; ok {
+			return id, nil
+		}
+		return "", fmt.Errorf("the variable %s is not defined", value)
+	}
+	for i := range document.Charts {
+		chart := &document.Charts[i]
+		var err error
+		if chart.LogGroupId, err = expand(chart.LogGroupId); err != nil {
+			return err
+		}
+		if chart.LogStreamId, err = expand(chart.LogStreamId); err != nil {
+			return err
+		}
+	}
+	return nil
+}
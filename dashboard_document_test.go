@@ -0,0 +1,302 @@
+package lts
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestNormalizeDashboardJson(t *testing.T) {
+	cases := []struct {
+		name    string
+		old     string
+		new     string
+		equal   bool
+		wantErr bool
+	}{
+		{
+			name:  "minimal document against the rendered one",
+			old:   `{"title":"apm","charts":[{"title":"pv","type":"line","sql":"select count(*)","config":{}}]}`,
+			new:   `{"title":"apm","charts":[{"title":"pv","type":"line","sql":"select count(*)"}]}`,
+			equal: true,
+		},
+		{
+			name:  "key order",
+			old:   `{"charts":[{"sql":"select 1","type":"number","title":"n"}],"title":"apm"}`,
+			new:   `{"title":"apm","charts":[{"title":"n","type":"number","sql":"select 1"}]}`,
+			equal: true,
+		},
+		{
+			name: "server fields",
+			old: `{"id":"d1","project_id":"p1","last_update_time":1700000000,"title":"apm",` +
+				`"charts":[{"id":"c1","dashboard_id":"d1","title":"n","type":"number","sql":"select 1"}]}`,
+			new:   `{"title":"apm","charts":[{"title":"n","type":"number","sql":"select 1"}]}`,
+			equal: true,
+		},
+		{
+			name:  "empty log target",
+			old:   `{"title":"apm","charts":[{"title":"n","type":"number","sql":"select 1","log_group_id":""}]}`,
+			new:   `{"title":"apm","charts":[{"title":"n","type":"number","sql":"select 1"}]}`,
+			equal: true,
+		},
+		{
+			name:  "changed sql",
+			old:   `{"title":"apm","charts":[{"title":"n","type":"number","sql":"select 1"}]}`,
+			new:   `{"title":"apm","charts":[{"title":"n","type":"number","sql":"select 2"}]}`,
+			equal: false,
+		},
+		{
+			name:  "chart order",
+			old:   `{"title":"apm","charts":[{"title":"a","type":"pie","sql":"x"},{"title":"b","type":"pie","sql":"y"}]}`,
+			new:   `{"title":"apm","charts":[{"title":"b","type":"pie","sql":"y"},{"title":"a","type":"pie","sql":"x"}]}`,
+			equal: false,
+		},
+		{
+			name:    "invalid document",
+			old:     `{"title":"apm","charts":[]}`,
+			new:     `{"title":`,
+			wantErr: true,
+		},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			oldJson, err := normalizeDashboardJson(c.old)
+			if err != nil {
+				t.Fatalf("unexpected error: %s", err)
+			}
+			newJson, err := normalizeDashboardJson(c.new)
+			if c.wantErr {
+				if err == nil {
+					t.Fatalf("expected an error, got %s", newJson)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error: %s", err)
+			}
+			if (oldJson == newJson) != c.equal {
+				t.Fatalf("got %s and %s, want equal = %t", oldJson, newJson, c.equal)
+			}
+			if suppressDashboardJsonDiffs("dashboard_json", c.old, c.new, nil) != c.equal {
+				t.Fatalf("suppressDashboardJsonDiffs does not match the normalized documents")
+			}
+		})
+	}
+}
+
+func TestValidateDashboardJson(t *testing.T) {
+	valid := []string{
+		`{"title":"apm","charts":[]}`,
+		`{"title":"apm","group_name":"ops","charts":[{"title":"pv","type":"line","sql":"select count(*)",` +
+			`"log_group_id":"${log_group_id}","log_stream_id":"${log_stream_id}",` +
+			`"config":{"time_range":{"relative_minutes":15},"layout":{"x":0,"y":0,"width":6,"height":4}}}]}`,
+		`{"title":"apm","charts":[{"id":"c1","dashboard_id":"d1","title":"n","type":"number","sql":"select 1"}]}`,
+		`{"title":"apm","charts":[],"filters":[{"field":"host","type":"dropdown","source_query":"select host"},` +
+			`{"field":"time","type":"time","default_values":["15m"]}]}`,
+	}
+	for _, document := range valid {
+		if _, errs := validateDashboardJson(document, "dashboard_json"); len(errs) > 0 {
+			t.Errorf("validateDashboardJson(%s) returned %v, want no errors", document, errs)
+		}
+	}
+
+	// the documents which are rejected, with a part of the expected error
+	invalid := [][2]string{
+		{`{"title":"apm","charts":[],"layout":"grid"}`, `unknown field "layout"`},
+		{`{"title":"apm","charts":[{"title":"n","type":"number","sql":"select 1","color":"red"}]}`,
+			`unknown field "color"`},
+		{`{"title":"apm","charts":[{"title":"n","type":"number","sql":"select 1","config":{"theme":"dark"}}]}`,
+			`unknown field "theme"`},
+		{`{"title":"apm","charts":[{"title":"n","type":"number","sql":"select 1",` +
+			`"config":{"layout":{"x":0,"depth":1}}}]}`, `unknown field "depth"`},
+		{`{"title":"apm","charts":[]}{}`, "data after the dashboard"},
+		{`{"title":"apm","charts":[{"title":"n","type":"gauge","sql":"select 1"}]}`, `invalid type "gauge"`},
+		{`{"title":"apm","charts":[{"title":"n","type":"table"}]}`, "has no sql"},
+		{`["apm"]`, "not a valid dashboard document"},
+		{`{"title":"apm","charts":[],"filters":["{\"field\":\"host\",\"type\":\"input\"}"]}`,
+			"not a valid dashboard document"},
+		{`{"title":"apm","charts":[],"filters":[{"field":"host","type":"list"}]}`, `invalid type "list"`},
+		{`{"title":"apm","charts":[],"filters":[{"field":"host","type":"input","source_query":"select host"}]}`,
+			"only supported for dropdown filters"},
+		{`{"title":"apm","charts":[],"filters":[{"field":"host","type":"input","values":["a"]}]}`,
+			`unknown field "values"`},
+	}
+	for _, c := range invalid {
+		_, errs := validateDashboardJson(c[0], "dashboard_json")
+		if len(errs) == 0 || !strings.Contains(errs[0].Error(), c[1]) {
+			t.Errorf("validateDashboardJson(%s) returned %v, want an error containing %q", c[0], errs, c[1])
+		}
+	}
+}
+
+func TestDashboardInfoRequestFilters(t *testing.T) {
+	info := dashboardInfo{
+		Title: "apm",
+		Filters: []dashBoardFilter{
+			{Field: "host", Type: dashBoardFilterDropdown, SourceQuery: "select distinct host"},
+			{Field: "time", Type: dashBoardFilterTime, DefaultValues: []string{"15m"}, AppliedCharts: []string{"c1"}},
+		},
+	}
+	request, err := buildDashboardInfoRequest(info)
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	wantFilters := []string{
+		`{"field":"host","type":"dropdown","source_query":"select distinct host"}`,
+		`{"field":"time","type":"time","default_values":["15m"],"applied_charts":["c1"]}`,
+	}
+	if !reflect.DeepEqual(request.Filters, wantFilters) {
+		t.Fatalf("got filters %q, want %q", request.Filters, wantFilters)
+	}
+
+	// the filters read back from the API, one of them double encoded and one which is not JSON
+	filters, invalid := decodeDashBoardFilters(append(request.Filters, `"{\"field\":\"env\",\"type\":\"input\"}"`,
+		"host=web"))
+	wantDecoded := append(info.Filters, dashBoardFilter{Field: "env", Type: dashBoardFilterInput})
+	if !reflect.DeepEqual(filters, wantDecoded) {
+		t.Errorf("got decoded filters %+v, want %+v", filters, wantDecoded)
+	}
+	if !reflect.DeepEqual(invalid, []string{"host=web"}) {
+		t.Errorf("got invalid filters %q, want [host=web]", invalid)
+	}
+}
+
+func TestTemplateDashboardDocument(t *testing.T) {
+	cases := []struct {
+		name      string
+		charts    [][2]string
+		bound     map[string]string
+		want      [][2]string
+		variables map[string]string
+	}{
+		{
+			name:      "one target",
+			charts:    [][2]string{{"g1", "s1"}, {"g1", "s1"}},
+			want:      [][2]string{{"${log_group_id}", "${log_stream_id}"}, {"${log_group_id}", "${log_stream_id}"}},
+			variables: map[string]string{"log_group_id": "g1", "log_stream_id": "s1"},
+		},
+		{
+			name:   "several targets",
+			charts: [][2]string{{"g1", "s1"}, {"g1", "s2"}, {"g2", "s3"}},
+			want: [][2]string{
+				{"${log_group_id}", "${log_stream_id}"},
+				{"${log_group_id}", "${log_stream_id_2}"},
+				{"${log_group_id_2}", "${log_stream_id_3}"},
+			},
+			variables: map[string]string{
+				"log_group_id": "g1", "log_group_id_2": "g2",
+				"log_stream_id": "s1", "log_stream_id_2": "s2", "log_stream_id_3": "s3",
+			},
+		},
+		{
+			name:   "bound target first",
+			charts: [][2]string{{"g1", "s1"}, {"g2", "s2"}},
+			bound:  map[string]string{"log_group_id": "g2", "log_stream_id": "s2"},
+			want:   [][2]string{{"${log_group_id_2}", "${log_stream_id_2}"}, {"${log_group_id}", "${log_stream_id}"}},
+			variables: map[string]string{
+				"log_group_id": "g2", "log_group_id_2": "g1", "log_stream_id": "s2", "log_stream_id_2": "s1",
+			},
+		},
+		{
+			name:   "bound variables keep their names",
+			charts: [][2]string{{"g1", "s1"}, {"g1", "s2"}, {"g1", "s3"}},
+			bound:  map[string]string{"log_group_id": "g1", "log_stream_id": "s1", "log_stream_id_3": "s2"},
+			want: [][2]string{
+				{"${log_group_id}", "${log_stream_id}"},
+				{"${log_group_id}", "${log_stream_id_3}"},
+				{"${log_group_id}", "${log_stream_id_2}"},
+			},
+			variables: map[string]string{
+				"log_group_id": "g1", "log_stream_id": "s1", "log_stream_id_2": "s3", "log_stream_id_3": "s2",
+			},
+		},
+		{
+			name:      "chart without target",
+			charts:    [][2]string{{"", ""}},
+			want:      [][2]string{{"", ""}},
+			variables: map[string]string{},
+		},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			document := dashboardDocument{}
+			for _, target := range c.charts {
+				document.Charts = append(document.Charts, dashboardChart{LogGroupId: target[0], LogStreamId: target[1]})
+			}
+			variables := templateDashboardDocument(&document, c.bound)
+			got := make([][2]string, len(document.Charts))
+			for i, chart := range document.Charts {
+				got[i] = [2]string{chart.LogGroupId, chart.LogStreamId}
+			}
+			if !reflect.DeepEqual(got, c.want) {
+				t.Fatalf("got charts %v, want %v", got, c.want)
+			}
+			if !reflect.DeepEqual(variables, c.variables) {
+				t.Fatalf("got variables %v, want %v", variables, c.variables)
+			}
+		})
+	}
+}
+
+func TestExpandDashboardDocument(t *testing.T) {
+	cases := []struct {
+		name      string
+		charts    [][2]string
+		variables map[string]string
+		want      [][2]string
+		wantErr   bool
+	}{
+		{
+			name:      "variables",
+			charts:    [][2]string{{"${log_group_id}", "${log_stream_id_2}"}},
+			variables: map[string]string{"log_group_id": "g1", "log_stream_id_2": "s2"},
+			want:      [][2]string{{"g1", "s2"}},
+		},
+		{
+			name:      "plain IDs",
+			charts:    [][2]string{{"g1", "s1"}},
+			variables: map[string]string{"log_group_id": "g2"},
+			want:      [][2]string{{"g1", "s1"}},
+		},
+		{
+			name:      "not a whole variable",
+			charts:    [][2]string{{"prefix-${log_group_id}", "${log_stream_id}"}},
+			variables: map[string]string{"log_group_id": "g1", "log_stream_id": "s1"},
+			want:      [][2]string{{"prefix-${log_group_id}", "s1"}},
+		},
+		{
+			name:      "undefined variable",
+			charts:    [][2]string{{"${log_group_id_2}", "s1"}},
+			variables: map[string]string{"log_group_id": "g1"},
+			wantErr:   true,
+		},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			document := dashboardDocument{}
+			for _, target := range c.charts {
+				document.Charts = append(document.Charts, dashboardChart{LogGroupId: target[0], LogStreamId: target[1]})
+			}
+			err := expandDashboardDocument(&document, c.variables)
+			if c.wantErr {
+				if err == nil {
+					t.Fatalf("expected an error, got %v", document.Charts)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error: %s", err)
+			}
+			got := make([][2]string, len(document.Charts))
+			for i, chart := range document.Charts {
+				got[i] = [2]string{chart.LogGroupId, chart.LogStreamId}
+			}
+			if !reflect.DeepEqual(got, c.want) {
+				t.Fatalf("got %v, want %v", got, c.want)
+			}
+		})
+	}
+}
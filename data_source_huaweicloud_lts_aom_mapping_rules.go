@@ -0,0 +1,250 @@
+package lts
+
+import (
+	"context"
+	"encoding/json"
+	"io/ioutil"
+
+	"github.com/hashicorp/go-multierror"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/huaweicloud/terraform-provider-huaweicloud/huaweicloud/config"
+	"github.com/huaweicloud/terraform-provider-huaweicloud/huaweicloud/services/internal/httpclient_go"
+	"github.com/huaweicloud/terraform-provider-huaweicloud/huaweicloud/utils/hashcode"
+)
+
+func DataSourceAomMappingRules() *schema.Resource {
+	return &schema.Resource{
+		ReadContext: dataSourceAomMappingRulesRead,
+
+		Schema: map[string]*schema.Schema{
+			"region": {
+				Type:     schema.TypeString,
+				Optional: true,
+				Computed: true,
+			},
+			"cluster_id": {
+				Type:     schema.TypeString,
+				Optional: true,
+			},
+			"name_space": {
+				Type:     schema.TypeString,
+				Optional: true,
+			},
+			"deployment": {
+				Type:     schema.TypeString,
+				Optional: true,
+			},
+			"rule_name": {
+				Type:     schema.TypeString,
+				Optional: true,
+			},
+			"target_log_stream_id": {
+				Type:     schema.TypeString,
+				Optional: true,
+			},
+			"rules": {
+				Type:     schema.TypeList,
+				Computed: true,
+				Elem: &schema.Resource{
+					Schema: map[string]*schema.Schema{
+						"rule_id": {
+							Type:     schema.TypeString,
+							Computed: true,
+						},
+						"rule_name": {
+							Type:     schema.TypeString,
+							Computed: true,
+						},
+						"cluster_id": {
+							Type:     schema.TypeString,
+							Computed: true,
+						},
+						"cluster_name": {
+							Type:     schema.TypeString,
+							Computed: true,
+						},
+						"name_space": {
+							Type:     schema.TypeString,
+							Computed: true,
+						},
+						"container_name": {
+							Type:     schema.TypeString,
+							Computed: true,
+						},
+						"deployments": {
+							Type:     schema.TypeList,
+							Computed: true,
+							Elem:     &schema.Schema{Type: schema.TypeString},
+						},
+						"workloads": {
+							Type:     schema.TypeList,
+							Computed: true,
+							Elem: &schema.Resource{
+								Schema: map[string]*schema.Schema{
+									"kind": {
+										Type:     schema.TypeString,
+										Computed: true,
+									},
+									"name": {
+										Type:     schema.TypeString,
+										Computed: true,
+									},
+								},
+							},
+						},
+						"label_selector": {
+							Type:     schema.TypeMap,
+							Computed: true,
+							Elem:     &schema.Schema{Type: schema.TypeString},
+						},
+						"all_workloads": {
+							Type:     schema.TypeBool,
+							Computed: true,
+						},
+						"files": {
+							Type:     schema.TypeList,
+							Computed: true,
+							Elem: &schema.Resource{
+								Schema: map[string]*schema.Schema{
+									"file_name": {
+										Type:     schema.TypeString,
+										Computed: true,
+									},
+									"log_stream_info": {
+										Type:     schema.TypeList,
+										Computed: true,
+										Elem: &schema.Resource{
+											Schema: map[string]*schema.Schema{
+												"target_log_group_id": {
+													Type:     schema.TypeString,
+													Computed: true,
+												},
+												"target_log_group_name": {
+													Type:     schema.TypeString,
+													Computed: true,
+												},
+												"target_log_stream_id": {
+													Type:     schema.TypeString,
+													Computed: true,
+												},
+												"target_log_stream_name": {
+													Type:     schema.TypeString,
+													Computed: true,
+												},
+											},
+										},
+									},
+								},
+							},
+						},
+					},
+				},
+			},
+		},
+	}
+}
+
+// listAomMappingRules queries all AOM to LTS mapping rules of the project.
+func listAomMappingRules(cfg *config.Config, region string) ([]aomMappingRuleRequest, diag.Diagnostics) {
+	client, diaErr := httpclient_go.NewHttpClientGo(cfg)
+	if diaErr != nil {
+		return nil, diaErr
+	}
+	header := make(map[string]string)
+	header["content-type"] = "application/json;charset=UTF8"
+	client.WithMethod(httpclient_go.MethodGet).
+		WithUrlWithoutEndpoint(cfg, "lts", region, "v2/"+cfg.GetProjectID(region)+"/lts/aom-mapping").
+		WithHeader(header)
+	response, err := client.Do()
+	if err != nil {
+		return nil, diag.Errorf("error querying AomMappingRules: %s", err)
+	}
+	defer response.Body.Close()
+	body, err := ioutil.ReadAll(response.Body)
+	if err != nil {
+		return nil, diag.Errorf("error convert data %s, %s", string(body), err)
+	}
+	if response.StatusCode != 200 {
+		return nil, diag.Errorf("error querying AomMappingRules: %s", string(body))
+	}
+	rlt := make([]aomMappingRuleRequest, 0)
+	if err := json.Unmarshal(body, &rlt); err != nil {
+		return nil, diag.Errorf("error convert data %s, %s", string(body), err)
+	}
+	return rlt, nil
+}
+
+func filterAomMappingRule(d *schema.ResourceData, rule aomMappingRuleRequest) bool {
+	if v, ok := d.GetOk("cluster_id"); ok && rule.RuleInfo.ClusterId != v.(string) {
+		return false
+	}
+	if v, ok := d.GetOk("name_space"); ok && rule.RuleInfo.Namespace != v.(string) {
+		return false
+	}
+	if v, ok := d.GetOk("rule_name"); ok && rule.RuleName != v.(string) {
+		return false
+	}
+	if v, ok := d.GetOk("deployment"); ok && !containsString(rule.RuleInfo.Deployments, v.(string)) {
+		return false
+	}
+	if v, ok := d.GetOk("target_log_stream_id"); ok {
+		for _, file := range rule.RuleInfo.Files {
+			if file.LogStreamInfo.TargetLogStreamId == v.(string) {
+				return true
+			}
+		}
+		return false
+	}
+	return true
+}
+
+func containsString(values []string, value string) bool {
+	for _, v := range values {
+		if v == value {
+			return true
+		}
+	}
+	return false
+}
+
+func dataSourceAomMappingRulesRead(_ context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	cfg := meta.(*config.Config)
+	region := cfg.GetRegion(d)
+	rules, diags := listAomMappingRules(cfg, region)
+	if diags != nil {
+		return diags
+	}
+
+	ids := make([]string, 0, len(rules))
+	rst := make([]map[string]interface{}, 0, len(rules))
+	for _, rule := range rules {
+		if !filterAomMappingRule(d, rule) {
+			continue
+		}
+		ids = append(ids, rule.RuleId)
+		rst = append(rst, map[string]interface{}{
+			"rule_id":        rule.RuleId,
+			"rule_name":      rule.RuleName,
+			"cluster_id":     rule.RuleInfo.ClusterId,
+			"cluster_name":   rule.RuleInfo.ClusterName,
+			"name_space":     rule.RuleInfo.Namespace,
+			"container_name": rule.RuleInfo.ContainerName,
+			"deployments":    rule.RuleInfo.Deployments,
+			"workloads":      flattenWorkloadOpts(rule.RuleInfo.Workloads),
+			"label_selector": rule.RuleInfo.LabelSelector,
+			"all_workloads":  rule.RuleInfo.AllWorkloads,
+			"files":          flattenFileOpts(rule.RuleInfo.Files),
+		})
+	}
+
+	d.SetId(hashcode.Strings(ids))
+	mErr := multierror.Append(nil,
+		d.Set("region", region),
+		d.Set("rules", rst),
+	)
+	if err := mErr.ErrorOrNil(); err != nil {
+		return diag.Errorf("error setting AomMappingRules fields: %s", err)
+	}
+	return nil
+}
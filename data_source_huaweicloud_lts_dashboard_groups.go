@@ -0,0 +1,84 @@
+package lts
+
+import (
+	"context"
+
+	"github.com/hashicorp/go-multierror"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/huaweicloud/terraform-provider-huaweicloud/huaweicloud/config"
+	"github.com/huaweicloud/terraform-provider-huaweicloud/huaweicloud/utils/hashcode"
+)
+
+func DataSourceLtsDashboardGroups() *schema.Resource {
+	return &schema.Resource{
+		ReadContext: dataSourceLtsDashBoardGroupsRead,
+
+		Schema: map[string]*schema.Schema{
+			"region": {
+				Type:     schema.TypeString,
+				Optional: true,
+				Computed: true,
+			},
+			"name": {
+				Type:     schema.TypeString,
+				Optional: true,
+			},
+			"groups": {
+				Type:     schema.TypeList,
+				Computed: true,
+				Elem: &schema.Resource{
+					Schema: map[string]*schema.Schema{
+						"id": {
+							Type:     schema.TypeString,
+							Computed: true,
+						},
+						"name": {
+							Type:     schema.TypeString,
+							Computed: true,
+						},
+						"dashboards": {
+							Type:     schema.TypeList,
+							Computed: true,
+							Elem:     &schema.Schema{Type: schema.TypeString},
+						},
+					},
+				},
+			},
+		},
+	}
+}
+
+func dataSourceLtsDashBoardGroupsRead(_ context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	cfg := meta.(*config.Config)
+	region := cfg.GetRegion(d)
+	groups, diags := listDashBoardGroups(cfg, region)
+	if diags != nil {
+		return diags
+	}
+
+	name := d.Get("name").(string)
+	ids := make([]string, 0, len(groups))
+	rst := make([]map[string]interface{}, 0, len(groups))
+	for _, group := range groups {
+		if name != "" && group.GroupName != name {
+			continue
+		}
+		ids = append(ids, group.Id)
+		rst = append(rst, map[string]interface{}{
+			"id":         group.Id,
+			"name":       group.GroupName,
+			"dashboards": group.Dashboards,
+		})
+	}
+
+	d.SetId(hashcode.Strings(ids))
+	mErr := multierror.Append(nil,
+		d.Set("region", region),
+		d.Set("groups", rst),
+	)
+	if err := mErr.ErrorOrNil(); err != nil {
+		return diag.Errorf("error setting LtsDashBoardGroups fields: %s", err)
+	}
+	return nil
+}
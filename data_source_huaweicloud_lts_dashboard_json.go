@@ -0,0 +1,75 @@
+package lts
+
+import (
+	"context"
+	"encoding/json"
+
+	"github.com/hashicorp/go-multierror"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/huaweicloud/terraform-provider-huaweicloud/huaweicloud/config"
+)
+
+func DataSourceLtsDashboardJson() *schema.Resource {
+	return &schema.Resource{
+		ReadContext: dataSourceLtsDashboardJsonRead,
+
+		Schema: map[string]*schema.Schema{
+			"region": {
+				Type:     schema.TypeString,
+				Optional: true,
+				Computed: true,
+			},
+			"dashboard_id": {
+				Type:     schema.TypeString,
+				Required: true,
+			},
+			"dashboard_json": {
+				Type:     schema.TypeString,
+				Computed: true,
+			},
+			"variables": {
+				Type:     schema.TypeMap,
+				Computed: true,
+				Elem:     &schema.Schema{Type: schema.TypeString},
+			},
+		},
+	}
+}
+
+func dataSourceLtsDashboardJsonRead(_ context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	cfg := meta.(*config.Config)
+	region := cfg.GetRegion(d)
+	dashBoardId := d.Get("dashboard_id").(string)
+	dashBoard, diags := getDashBoard(cfg, region, dashBoardId)
+	if diags != nil {
+		return diags
+	}
+	if dashBoard == nil {
+		return diag.Errorf("error querying LtsDashBoard %s: not found", dashBoardId)
+	}
+	document, diags := renderDashboardDocument(cfg, region, dashBoard)
+	if diags != nil {
+		return diags
+	}
+	variables := templateDashboardDocument(document, nil)
+	b, err := json.Marshal(document)
+	if err != nil {
+		return diag.Errorf("error rendering LtsDashBoard %s: %s", dashBoardId, err)
+	}
+	dashBoardJson, err := normalizeDashboardJson(string(b))
+	if err != nil {
+		return diag.Errorf("error rendering LtsDashBoard %s: %s", dashBoardId, err)
+	}
+
+	d.SetId(dashBoardId)
+	mErr := multierror.Append(nil,
+		d.Set("region", region),
+		d.Set("dashboard_json", dashBoardJson),
+		d.Set("variables", variables),
+	)
+	if err := mErr.ErrorOrNil(); err != nil {
+		return diag.Errorf("error setting LtsDashboardJson fields: %s", err)
+	}
+	return nil
+}
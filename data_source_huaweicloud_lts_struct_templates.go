@@ -0,0 +1,201 @@
+package lts
+
+import (
+	"context"
+	"io/ioutil"
+
+	"github.com/hashicorp/go-multierror"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/huaweicloud/terraform-provider-huaweicloud/huaweicloud/config"
+	"github.com/huaweicloud/terraform-provider-huaweicloud/huaweicloud/services/internal/entity"
+	"github.com/huaweicloud/terraform-provider-huaweicloud/huaweicloud/services/internal/httpclient_go"
+	"github.com/huaweicloud/terraform-provider-huaweicloud/huaweicloud/utils/hashcode"
+)
+
+// systemStructTemplate is an element of the system struct template list returned by the v3 API.
+type systemStructTemplate struct {
+	Id           string                  `json:"id"`
+	TemplateName string                  `json:"template_name"`
+	TemplateType string                  `json:"template_type"`
+	DemoLog      string                  `json:"demo_log"`
+	DemoFields   []entity.DemoFieldsInfo `json:"demo_fields"`
+	TagFields    []entity.TagFieldsInfo  `json:"tag_fields"`
+}
+
+type systemStructTemplateList struct {
+	Results []systemStructTemplate `json:"results"`
+}
+
+func DataSourceLtsStructTemplates() *schema.Resource {
+	return &schema.Resource{
+		ReadContext: dataSourceLtsStructTemplatesRead,
+
+		Schema: map[string]*schema.Schema{
+			"region": {
+				Type:     schema.TypeString,
+				Optional: true,
+				Computed: true,
+			},
+			"name": {
+				Type:     schema.TypeString,
+				Optional: true,
+			},
+			"template_type": {
+				Type:     schema.TypeString,
+				Optional: true,
+			},
+			"templates": {
+				Type:     schema.TypeList,
+				Computed: true,
+				Elem: &schema.Resource{
+					Schema: map[string]*schema.Schema{
+						"id": {
+							Type:     schema.TypeString,
+							Computed: true,
+						},
+						"name": {
+							Type:     schema.TypeString,
+							Computed: true,
+						},
+						"template_type": {
+							Type:     schema.TypeString,
+							Computed: true,
+						},
+						"demo_log": {
+							Type:     schema.TypeString,
+							Computed: true,
+						},
+						"demo_fields": {
+							Type:     schema.TypeList,
+							Computed: true,
+							Elem: &schema.Resource{
+								Schema: map[string]*schema.Schema{
+									"field_name": {
+										Type:     schema.TypeString,
+										Computed: true,
+									},
+									"type": {
+										Type:     schema.TypeString,
+										Computed: true,
+									},
+									"content": {
+										Type:     schema.TypeString,
+										Computed: true,
+									},
+									"is_analysis": {
+										Type:     schema.TypeBool,
+										Computed: true,
+									},
+									"user_defined_name": {
+										Type:     schema.TypeString,
+										Computed: true,
+									},
+									"index": {
+										Type:     schema.TypeInt,
+										Computed: true,
+									},
+								},
+							},
+						},
+						"tag_fields": {
+							Type:     schema.TypeList,
+							Computed: true,
+							Elem: &schema.Resource{
+								Schema: map[string]*schema.Schema{
+									"field_name": {
+										Type:     schema.TypeString,
+										Computed: true,
+									},
+									"type": {
+										Type:     schema.TypeString,
+										Computed: true,
+									},
+									"content": {
+										Type:     schema.TypeString,
+										Computed: true,
+									},
+									"is_analysis": {
+										Type:     schema.TypeBool,
+										Computed: true,
+									},
+								},
+							},
+						},
+					},
+				},
+			},
+		},
+	}
+}
+
+// listSystemStructTemplates queries the built-in struct templates, e.g. ELB, VPC, CTS, APIG and nginx.
+func listSystemStructTemplates(cfg *config.Config, region string) ([]systemStructTemplate, diag.Diagnostics) {
+	client, diaErr := httpclient_go.NewHttpClientGo(cfg)
+	if diaErr != nil {
+		return nil, diaErr
+	}
+	header := make(map[string]string)
+	header["content-type"] = "application/json;charset=UTF8"
+	client.WithMethod(httpclient_go.MethodGet).
+		WithUrlWithoutEndpoint(cfg, "lts", region, "v3/"+cfg.GetProjectID(region)+"/lts/struct/template/system").
+		WithHeader(header)
+	response, err := client.Do()
+	if err != nil {
+		return nil, diag.Errorf("error querying system StructTemplates: %s", err)
+	}
+	defer response.Body.Close()
+	body, err := ioutil.ReadAll(response.Body)
+	if err != nil {
+		return nil, diag.Errorf("error convert data %s, %s", string(body), err)
+	}
+	if response.StatusCode != 200 {
+		return nil, diag.Errorf("error querying system StructTemplates: %s", string(body))
+	}
+	rlt := systemStructTemplateList{}
+	if err := unmarshalEmbeddedJSON(body, &rlt); err != nil {
+		return nil, diag.Errorf("error decoding system StructTemplates %s: %s", string(body), err)
+	}
+	return rlt.Results, nil
+}
+
+func dataSourceLtsStructTemplatesRead(_ context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	cfg := meta.(*config.Config)
+	region := cfg.GetRegion(d)
+	templates, diags := listSystemStructTemplates(cfg, region)
+	if diags != nil {
+		return diags
+	}
+
+	name := d.Get("name").(string)
+	templateType := d.Get("template_type").(string)
+	ids := make([]string, 0, len(templates))
+	rst := make([]map[string]interface{}, 0, len(templates))
+	for _, template := range templates {
+		if name != "" && template.TemplateName != name {
+			continue
+		}
+		if templateType != "" && template.TemplateType != templateType {
+			continue
+		}
+		ids = append(ids, template.Id)
+		rst = append(rst, map[string]interface{}{
+			"id":            template.Id,
+			"name":          template.TemplateName,
+			"template_type": template.TemplateType,
+			"demo_log":      template.DemoLog,
+			"demo_fields":   flattenDemoFieldsInfo(template.DemoFields),
+			"tag_fields":    flattenTagFieldsInfo(template.TagFields),
+		})
+	}
+
+	d.SetId(hashcode.Strings(ids))
+	mErr := multierror.Append(nil,
+		d.Set("region", region),
+		d.Set("templates", rst),
+	)
+	if err := mErr.ErrorOrNil(); err != nil {
+		return diag.Errorf("error setting LtsStructTemplates fields: %s", err)
+	}
+	return nil
+}
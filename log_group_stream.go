@@ -0,0 +1,204 @@
+package lts
+
+import (
+	"encoding/json"
+	"io/ioutil"
+	"strings"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/huaweicloud/terraform-provider-huaweicloud/huaweicloud/config"
+	"github.com/huaweicloud/terraform-provider-huaweicloud/huaweicloud/services/internal/httpclient_go"
+)
+
+type ltsLogGroup struct {
+	LogGroupId   string `json:"log_group_id,omitempty"`
+	LogGroupName string `json:"log_group_name"`
+	TTLInDays    int    `json:"ttl_in_days,omitempty"`
+}
+
+type ltsLogGroupList struct {
+	LogGroups []ltsLogGroup `json:"log_groups"`
+}
+
+type ltsLogStream struct {
+	LogStreamId   string `json:"log_stream_id,omitempty"`
+	LogStreamName string `json:"log_stream_name"`
+}
+
+type ltsLogStreamList struct {
+	LogStreams []ltsLogStream `json:"log_streams"`
+}
+
+type createLogGroupResp struct {
+	LogGroupId string `json:"log_group_id"`
+}
+
+type createLogStreamResp struct {
+	LogStreamId string `json:"log_stream_id"`
+}
+
+// doLtsRequest sends a request to the LTS API of the project and decodes the response into rlt when it is not nil.
+// Status codes other than 200, 201 and 204 are returned as errors, except 404 which is left to the caller.
+func doLtsRequest(cfg *config.Config, region, method, path string, reqBody, rlt interface{}) (int, diag.Diagnostics) {
+	client, diaErr := httpclient_go.NewHttpClientGo(cfg)
+	if diaErr != nil {
+		return 0, diaErr
+	}
+	header := make(map[string]string)
+	header["content-type"] = "application/json;charset=UTF8"
+	client.WithMethod(method).
+		WithUrlWithoutEndpoint(cfg, "lts", region, "v2/"+cfg.GetProjectID(region)+path).
+		WithHeader(header)
+	if reqBody != nil {
+		client.WithBody(reqBody)
+	}
+	response, err := client.Do()
+	if err != nil {
+		return 0, diag.Errorf("error requesting %s %s: %s", method, path, err)
+	}
+	defer response.Body.Close()
+	body, err := ioutil.ReadAll(response.Body)
+	if err != nil {
+		return response.StatusCode, diag.Errorf("error convert data %s, %s", string(body), err)
+	}
+	switch response.StatusCode {
+	case 200, 201, 204:
+	case 404:
+		return response.StatusCode, nil
+	default:
+		return response.StatusCode, diag.Errorf("error requesting %s %s: %s", method, path, string(body))
+	}
+	if rlt != nil {
+		if err := json.Unmarshal(body, rlt); err != nil {
+			return response.StatusCode, diag.Errorf("error convert data %s, %s", string(body), err)
+		}
+	}
+	return response.StatusCode, nil
+}
+
+// logTargetResolver looks up log groups and log streams by ID or by name. Each list is queried once, so a resolver
+// should only live as long as one operation. With createMissing set, the targets given only by name are created
+// and recorded in createdGroups and createdStreams.
+type logTargetResolver struct {
+	cfg     *config.Config
+	region  string
+	groups  []ltsLogGroup
+	streams map[string][]ltsLogStream
+
+	createMissing  bool
+	ttlInDays      int
+	createdGroups  []string
+	createdStreams []string
+}
+
+func newLogTargetResolver(cfg *config.Config, region string) *logTargetResolver {
+	return &logTargetResolver{
+		cfg:     cfg,
+		region:  region,
+		streams: make(map[string][]ltsLogStream),
+	}
+}
+
+func (r *logTargetResolver) get(path string, rlt interface{}) diag.Diagnostics {
+	statusCode, diags := doLtsRequest(r.cfg, r.region, httpclient_go.MethodGet, path, nil, rlt)
+	if diags == nil && statusCode == 404 {
+		return diag.Errorf("error querying %s: not found", path)
+	}
+	return diags
+}
+
+// logGroup returns the log group with the given ID, or with the given name when the ID is empty. A nil group is
+// returned if there is no such group.
+func (r *logTargetResolver) logGroup(id, name string) (*ltsLogGroup, diag.Diagnostics) {
+	if r.groups == nil {
+		rlt := ltsLogGroupList{}
+		if diags := r.get("/groups", &rlt); diags != nil {
+			return nil, diags
+		}
+		r.groups = rlt.LogGroups
+	}
+	for i, group := range r.groups {
+		if (id != "" && group.LogGroupId == id) || (id == "" && group.LogGroupName == name) {
+			return &r.groups[i], nil
+		}
+	}
+	return nil, nil
+}
+
+// logStream returns the log stream of a group with the given ID, or with the given name when the ID is empty.
+// A nil stream is returned if there is no such stream.
+func (r *logTargetResolver) logStream(groupId, id, name string) (*ltsLogStream, diag.Diagnostics) {
+	streams, ok := r.streams[groupId]
+	if !ok {
+		rlt := ltsLogStreamList{}
+		if diags := r.get("/groups/"+groupId+"/streams", &rlt); diags != nil {
+			return nil, diags
+		}
+		streams = rlt.LogStreams
+		r.streams[groupId] = streams
+	}
+	for i, stream := range streams {
+		if (id != "" && stream.LogStreamId == id) || (id == "" && stream.LogStreamName == name) {
+			return &streams[i], nil
+		}
+	}
+	return nil, nil
+}
+
+// createLogGroup creates a log group and records it as created by the resolver.
+func (r *logTargetResolver) createLogGroup(name string) (*ltsLogGroup, diag.Diagnostics) {
+	opts := ltsLogGroup{
+		LogGroupName: name,
+		TTLInDays:    r.ttlInDays,
+	}
+	rlt := createLogGroupResp{}
+	statusCode, diags := doLtsRequest(r.cfg, r.region, httpclient_go.MethodPost, "/groups", opts, &rlt)
+	if diags == nil && (statusCode == 404 || rlt.LogGroupId == "") {
+		diags = diag.Errorf("error creating log group %s: no log group ID returned", name)
+	}
+	if diags != nil {
+		return nil, diags
+	}
+	opts.LogGroupId = rlt.LogGroupId
+	r.groups = append(r.groups, opts)
+	r.streams[opts.LogGroupId] = []ltsLogStream{}
+	r.createdGroups = append(r.createdGroups, opts.LogGroupId)
+	return &opts, nil
+}
+
+// createLogStream creates a log stream in a group and records it as created by the resolver.
+func (r *logTargetResolver) createLogStream(groupId, name string) (*ltsLogStream, diag.Diagnostics) {
+	opts := ltsLogStream{
+		LogStreamName: name,
+	}
+	rlt := createLogStreamResp{}
+	statusCode, diags := doLtsRequest(r.cfg, r.region, httpclient_go.MethodPost, "/groups/"+groupId+"/streams",
+		opts, &rlt)
+	if diags == nil && (statusCode == 404 || rlt.LogStreamId == "") {
+		diags = diag.Errorf("error creating log stream %s: no log stream ID returned", name)
+	}
+	if diags != nil {
+		return nil, diags
+	}
+	opts.LogStreamId = rlt.LogStreamId
+	r.streams[groupId] = append(r.streams[groupId], opts)
+	r.createdStreams = append(r.createdStreams, groupId+"/"+opts.LogStreamId)
+	return &opts, nil
+}
+
+// deleteCreatedLogTargets deletes the log streams and then the log groups created by a resource. The streams are
+// given as <log_group_id>/<log_stream_id>, targets which are already gone are skipped.
+func deleteCreatedLogTargets(cfg *config.Config, region string, groups, streams []string) diag.Diagnostics {
+	for _, stream := range streams {
+		if _, diags := doLtsRequest(cfg, region, httpclient_go.MethodDelete,
+			"/groups/"+strings.Replace(stream, "/", "/streams/", 1), nil, nil); diags != nil {
+			return diags
+		}
+	}
+	for _, group := range groups {
+		if _, diags := doLtsRequest(cfg, region, httpclient_go.MethodDelete, "/groups/"+group, nil, nil); diags != nil {
+			return diags
+		}
+	}
+	return nil
+}
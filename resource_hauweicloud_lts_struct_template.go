@@ -2,17 +2,20 @@ package lts
 
 import (
 	"context"
-	"encoding/json"
+	"fmt"
 	"github.com/hashicorp/go-multierror"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
 	"github.com/huaweicloud/terraform-provider-huaweicloud/huaweicloud/config"
 	"github.com/huaweicloud/terraform-provider-huaweicloud/huaweicloud/services/internal/entity"
 	"github.com/huaweicloud/terraform-provider-huaweicloud/huaweicloud/services/internal/httpclient_go"
+	"github.com/huaweicloud/terraform-provider-huaweicloud/huaweicloud/utils"
 	"github.com/huaweicloud/terraform-provider-huaweicloud/huaweicloud/utils/fmtp"
 	"io/ioutil"
+	"log"
 	"strings"
 
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/validation"
 )
 
 func ResourceLtsStruct() *schema.Resource {
@@ -21,6 +24,11 @@ func ResourceLtsStruct() *schema.Resource {
 		ReadContext:   resourceLtsStructTemplateRead,
 		DeleteContext: resourceLtsStructTemplateDelete,
 		UpdateContext: resourceLtsStructTemplateUpdate,
+		Importer: &schema.ResourceImporter{
+			StateContext: resourceLtsStructTemplateImportState,
+		},
+
+		CustomizeDiff: resourceLtsStructTemplateCustomizeDiff,
 
 		Schema: map[string]*schema.Schema{
 			"region": {
@@ -36,10 +44,12 @@ func ResourceLtsStruct() *schema.Resource {
 			"log_group_id": {
 				Type:     schema.TypeString,
 				Required: true,
+				ForceNew: true,
 			},
 			"log_stream_id": {
 				Type:     schema.TypeString,
 				Required: true,
+				ForceNew: true,
 			},
 			"project_id": {
 				Type:     schema.TypeString,
@@ -48,10 +58,13 @@ func ResourceLtsStruct() *schema.Resource {
 			"template_id": {
 				Type:     schema.TypeString,
 				Optional: true,
+				Computed: true,
 			},
 			"template_type": {
-				Type:     schema.TypeString,
-				Required: true,
+				Type:         schema.TypeString,
+				Required:     true,
+				ForceNew:     true,
+				ValidateFunc: validation.StringInSlice([]string{"built_in", "custom"}, false),
 			},
 			"template_name": {
 				Type:     schema.TypeString,
@@ -60,26 +73,38 @@ func ResourceLtsStruct() *schema.Resource {
 			"parse_type": {
 				Type:     schema.TypeString,
 				Optional: true,
+				Computed: true,
+				ValidateFunc: validation.StringInSlice([]string{
+					parseTypeSplit, parseTypeJson, parseTypeRegex, parseTypeNginx,
+				}, false),
 			},
 			"regex_rules": {
-				Type:     schema.TypeString,
-				Optional: true,
+				Type:          schema.TypeString,
+				Optional:      true,
+				ValidateFunc:  validateStructRegexRules,
+				ConflictsWith: []string{"tokenizer", "layers", "log_format"},
 			},
 			"layers": {
-				Type:     schema.TypeInt,
-				Optional: true,
+				Type:          schema.TypeInt,
+				Optional:      true,
+				ValidateFunc:  validation.IntBetween(1, 4),
+				ConflictsWith: []string{"tokenizer", "log_format"},
 			},
 			"tokenizer": {
-				Type:     schema.TypeString,
-				Optional: true,
+				Type:          schema.TypeString,
+				Optional:      true,
+				ConflictsWith: []string{"log_format"},
 			},
 			"log_format": {
-				Type:     schema.TypeString,
-				Optional: true,
+				Type:             schema.TypeString,
+				Optional:         true,
+				ValidateFunc:     validateNginxLogFormat,
+				DiffSuppressFunc: suppressNginxLogFormatDiffs,
 			},
 			"demo_fields": {
 				Type:     schema.TypeList,
 				Optional: true,
+				Computed: true,
 				Elem: &schema.Resource{
 					Schema: map[string]*schema.Schema{
 						"is_analysis": {
@@ -91,8 +116,9 @@ func ResourceLtsStruct() *schema.Resource {
 							Optional: true,
 						},
 						"type": {
-							Type:     schema.TypeString,
-							Optional: true,
+							Type:         schema.TypeString,
+							Optional:     true,
+							ValidateFunc: validation.StringInSlice([]string{fieldTypeString, fieldTypeLong, fieldTypeFloat}, false),
 						},
 						"field_name": {
 							Type:     schema.TypeString,
@@ -112,6 +138,7 @@ func ResourceLtsStruct() *schema.Resource {
 			"tag_fields": {
 				Type:     schema.TypeList,
 				Optional: true,
+				Computed: true,
 				Elem: &schema.Resource{
 					Schema: map[string]*schema.Schema{
 						"field_name": {
@@ -119,8 +146,9 @@ func ResourceLtsStruct() *schema.Resource {
 							Optional: true,
 						},
 						"type": {
-							Type:     schema.TypeString,
-							Optional: true,
+							Type:         schema.TypeString,
+							Optional:     true,
+							ValidateFunc: validation.StringInSlice([]string{fieldTypeString, fieldTypeLong, fieldTypeFloat}, false),
 						},
 						"content": {
 							Type:     schema.TypeString,
@@ -137,72 +165,336 @@ func ResourceLtsStruct() *schema.Resource {
 				Type:     schema.TypeString,
 				Computed: true,
 			},
+			"parsed_fields": {
+				Type:     schema.TypeList,
+				Computed: true,
+				Elem: &schema.Resource{
+					Schema: map[string]*schema.Schema{
+						"field_name": {
+							Type:     schema.TypeString,
+							Computed: true,
+						},
+						"type": {
+							Type:     schema.TypeString,
+							Computed: true,
+						},
+						"content": {
+							Type:     schema.TypeString,
+							Computed: true,
+						},
+						"index": {
+							Type:     schema.TypeInt,
+							Computed: true,
+						},
+					},
+				},
+			},
 		},
 	}
 }
 
-// func buildDemoFieldsInfo(rawDimensions []interface{}) []entity.DemoFieldsInfo {
-// 	if len(rawDimensions) == 0 {
-// 		return nil
-// 	}
-// 	dimensions := make([]entity.DemoFieldsInfo, len(rawDimensions))
-// 	for i, rawdimension := range rawDimensions {
-// 		dimension := rawdimension.(map[string]interface{})
-// 		dimensions[i] = entity.DemoFieldsInfo{
-// 			IsAnalysis:      dimension["is_analysis"].(bool),
-// 			Content:         dimension["content"].(string),
-// 			FieldName:       dimension["field_name"].(string),
-// 			Type:            dimension["type"].(string),
-// 			UserDefinedName: dimension["user_defined_name"].(string),
-// 			Index:           dimension["index"].(int),
-// 		}
-// 	}
-// 	return dimensions
-// }
+func buildDemoFieldsInfo(rawDimensions []interface{}) []entity.DemoFieldsInfo {
+	if len(rawDimensions) == 0 {
+		return nil
+	}
+	dimensions := make([]entity.DemoFieldsInfo, len(rawDimensions))
+	for i, rawdimension := range rawDimensions {
+		dimension := rawdimension.(map[string]interface{})
+		dimensions[i] = entity.DemoFieldsInfo{
+			IsAnalysis:      dimension["is_analysis"].(bool),
+			Content:         dimension["content"].(string),
+			FieldName:       dimension["field_name"].(string),
+			Type:            dimension["type"].(string),
+			UserDefinedName: dimension["user_defined_name"].(string),
+			Index:           dimension["index"].(int),
+		}
+	}
+	return dimensions
+}
+
+func buildTagFieldsInfo(rawDimensions []interface{}) []entity.TagFieldsInfo {
+	if len(rawDimensions) == 0 {
+		return nil
+	}
+	dimensions := make([]entity.TagFieldsInfo, len(rawDimensions))
+	for i, rawdimension := range rawDimensions {
+		dimension := rawdimension.(map[string]interface{})
+		dimensions[i] = entity.TagFieldsInfo{
+			FieldName:  dimension["field_name"].(string),
+			Type:       dimension["type"].(string),
+			Content:    utils.String(dimension["content"].(string)),
+			IsAnalysis: utils.Bool(dimension["is_analysis"].(bool)),
+		}
+	}
+	return dimensions
+}
+
+// buildStructTemplateRequest builds the request body from the schema. Custom templates carry their own sample log,
+// parse rules and field definitions, system templates are referenced by their ID, name and type. Both kinds send
+// the demo and tag fields, which control the field names and which fields are analyzed.
+func buildStructTemplateRequest(d *schema.ResourceData) entity.StructTemplateRequest {
+	opts := entity.StructTemplateRequest{
+		LogGroupId:  d.Get("log_group_id").(string),
+		LogStreamId: d.Get("log_stream_id").(string),
+		TagFields:   buildTagFieldsInfo(d.Get("tag_fields").([]interface{})),
+	}
+	if d.Get("template_type").(string) == "custom" {
+		opts.Content = d.Get("content").(string)
+		opts.ParseType = d.Get("parse_type").(string)
+		opts.Tokenizer = d.Get("tokenizer").(string)
+		opts.RegexRules = d.Get("regex_rules").(string)
+		opts.Layers = d.Get("layers").(int)
+		opts.LogFormat = nginxLogFormatString(d.Get("log_format").(string))
+		if opts.ParseType == "" && opts.LogFormat != "" {
+			opts.ParseType = parseTypeNginx
+		}
+		rawDemoFields := d.GetRawConfig().GetAttr("demo_fields")
+		if opts.ParseType == parseTypeNginx && (rawDemoFields.IsNull() || rawDemoFields.LengthInt() == 0) {
+			opts.DemoFields = buildNginxDemoFieldsInfo(opts.LogFormat, opts.Content)
+		} else {
+			opts.DemoFields = buildDemoFieldsInfo(d.Get("demo_fields").([]interface{}))
+		}
+		return opts
+	}
+	opts.TemplateId = d.Get("template_id").(string)
+	opts.TemplateType = d.Get("template_type").(string)
+	opts.TemplateName = d.Get("template_name").(string)
+	opts.DemoFields = buildDemoFieldsInfo(d.Get("demo_fields").([]interface{}))
+	return opts
+}
+
+func flattenDemoFieldsInfo(fields []entity.DemoFieldsInfo) []map[string]interface{} {
+	if len(fields) == 0 {
+		return nil
+	}
+	rst := make([]map[string]interface{}, len(fields))
+	for i, field := range fields {
+		rst[i] = map[string]interface{}{
+			"is_analysis":       field.IsAnalysis,
+			"content":           field.Content,
+			"type":              field.Type,
+			"field_name":        field.FieldName,
+			"user_defined_name": field.UserDefinedName,
+			"index":             field.Index,
+		}
+	}
+	return rst
+}
+
+func flattenTagFieldsInfo(fields []entity.TagFieldsInfo) []map[string]interface{} {
+	if len(fields) == 0 {
+		return nil
+	}
+	rst := make([]map[string]interface{}, len(fields))
+	for i, field := range fields {
+		tagField := map[string]interface{}{
+			"field_name": field.FieldName,
+			"type":       field.Type,
+		}
+		if field.Content != nil {
+			tagField["content"] = *field.Content
+		}
+		if field.IsAnalysis != nil {
+			tagField["is_analysis"] = *field.IsAnalysis
+		}
+		rst[i] = tagField
+	}
+	return rst
+}
+
+// resolveSystemStructTemplateId looks up the ID of a system template by its name when template_id is not configured.
+func resolveSystemStructTemplateId(d *schema.ResourceData, cfg *config.Config) diag.Diagnostics {
+	if d.Get("template_type").(string) == "custom" || !d.GetRawConfig().GetAttr("template_id").IsNull() {
+		return nil
+	}
+	name := d.Get("template_name").(string)
+	if name == "" {
+		return diag.Errorf("either template_id or template_name must be specified for system StructTemplates")
+	}
+	templates, diags := listSystemStructTemplates(cfg, cfg.GetRegion(d))
+	if diags != nil {
+		return diags
+	}
+	for _, template := range templates {
+		if template.TemplateName == name {
+			return diag.FromErr(d.Set("template_id", template.Id))
+		}
+	}
+	return diag.Errorf("unable to find the system StructTemplate %s", name)
+}
+
+// buildNginxDemoFieldsInfo builds one field per variable of the nginx log_format, the field contents are taken from
+// the sample log when it matches the format.
+func buildNginxDemoFieldsInfo(logFormat, content string) []entity.DemoFieldsInfo {
+	fields, err := parseNginxSample(logFormat, content)
+	if err != nil {
+		if fields, err = nginxLogFormatFields(logFormat); err != nil {
+			return nil
+		}
+	}
+	rst := make([]entity.DemoFieldsInfo, len(fields))
+	for i, field := range fields {
+		rst[i] = entity.DemoFieldsInfo{
+			FieldName: field.FieldName,
+			Type:      field.Type,
+			Content:   field.Content,
+			Index:     field.Index,
+		}
+	}
+	return rst
+}
+
+func suppressNginxLogFormatDiffs(_, old, new string, _ *schema.ResourceData) bool {
+	return nginxLogFormatString(old) == nginxLogFormatString(new)
+}
+
+func buildStructParseRules(get func(string) interface{}) structParseRules {
+	rules := structParseRules{
+		ParseType:  get("parse_type").(string),
+		Tokenizer:  get("tokenizer").(string),
+		RegexRules: get("regex_rules").(string),
+		Layers:     get("layers").(int),
+		LogFormat:  get("log_format").(string),
+	}
+	if rules.ParseType == "" && rules.LogFormat != "" {
+		rules.ParseType = parseTypeNginx
+	}
+	return rules
+}
+
+func flattenParsedStructFields(fields []parsedStructField) []map[string]interface{} {
+	rst := make([]map[string]interface{}, len(fields))
+	for i, field := range fields {
+		rst[i] = map[string]interface{}{
+			"field_name": field.FieldName,
+			"type":       field.Type,
+			"content":    field.Content,
+			"index":      field.Index,
+		}
+	}
+	return rst
+}
+
+// structParseTypeArguments maps the parse types to the argument which holds their rules, only the JSON layers are
+// optional.
+var structParseTypeArguments = map[string]string{
+	parseTypeSplit: "tokenizer",
+	parseTypeJson:  "layers",
+	parseTypeRegex: "regex_rules",
+	parseTypeNginx: "log_format",
+}
+
+var customStructTemplateArguments = []string{"content", "parse_type", "tokenizer", "regex_rules", "layers", "log_format"}
+
+func validateStructRegexRules(v interface{}, k string) ([]string, []error) {
+	if structRegexNeedsJava(v.(string)) {
+		return []string{fmt.Sprintf("%q uses Java regex features which can not be checked before apply", k)}, nil
+	}
+	if _, err := compileStructRegex(v.(string)); err != nil {
+		return nil, []error{fmt.Errorf("%q is not a valid struct template regex: %s", k, err)}
+	}
+	return nil, nil
+}
+
+func validateNginxLogFormat(v interface{}, k string) ([]string, []error) {
+	if _, err := nginxLogFormatRegexp(v.(string)); err != nil {
+		return nil, []error{fmt.Errorf("%q is not a valid nginx log format: %s", k, err)}
+	}
+	return nil, nil
+}
+
+// checkStructTemplateArguments checks that the arguments match the template type and the parse type, the API only
+// reports these mistakes as a bad request.
+func checkStructTemplateArguments(d *schema.ResourceDiff) error {
+	// the arguments depend on the template type, which may only be known on apply
+	if !d.NewValueKnown("template_type") {
+		return nil
+	}
+	rawConfig := d.GetRawConfig()
+	if d.Get("template_type").(string) != "custom" {
+		for _, key := range customStructTemplateArguments {
+			if !rawConfig.GetAttr(key).IsNull() {
+				return fmt.Errorf("%s can only be specified for custom templates", key)
+			}
+		}
+		if rawConfig.GetAttr("template_id").IsNull() && rawConfig.GetAttr("template_name").IsNull() {
+			return fmt.Errorf("either template_id or template_name must be specified for built_in templates")
+		}
+		return nil
+	}
+
+	if rawConfig.GetAttr("content").IsNull() {
+		return fmt.Errorf("content is required for custom templates")
+	}
+	parseType := buildStructParseRules(d.Get).ParseType
+	if parseType == "" {
+		return fmt.Errorf("parse_type is required for custom templates")
+	}
+	for mode, key := range structParseTypeArguments {
+		configured := !rawConfig.GetAttr(key).IsNull()
+		if mode != parseType && configured {
+			return fmt.Errorf("%s can only be used with parse_type %q", key, mode)
+		}
+		if mode == parseType && !configured && mode != parseTypeJson {
+			return fmt.Errorf("%s is required when parse_type is %q", key, mode)
+		}
+	}
+	return nil
+}
+
+// resourceLtsStructTemplateCustomizeDiff parses the sample log of custom templates locally, so the plan shows the
+// extracted fields or fails when the sample does not match the parse rules.
+func resourceLtsStructTemplateCustomizeDiff(_ context.Context, d *schema.ResourceDiff, _ interface{}) error {
+	if err := checkStructTemplateArguments(d); err != nil {
+		return err
+	}
+	if !d.NewValueKnown("template_type") {
+		return d.SetNewComputed("parsed_fields")
+	}
+	if d.Get("template_type").(string) != "custom" {
+		return nil
+	}
+	for _, key := range customStructTemplateArguments {
+		if !d.NewValueKnown(key) {
+			return d.SetNewComputed("parsed_fields")
+		}
+	}
+	rules := buildStructParseRules(d.Get)
+	if rules.ParseType == parseTypeRegex && structRegexNeedsJava(rules.RegexRules) {
+		return d.SetNewComputed("parsed_fields")
+	}
+	fields, err := parseStructSample(rules, d.Get("content").(string))
+	if err != nil {
+		return fmt.Errorf("the sample log in content can not be parsed with parse_type %q: %s",
+			rules.ParseType, err)
+	}
+	return d.SetNew("parsed_fields", flattenParsedStructFields(fields))
+}
+
+// buildStructTemplateUrl returns the struct template endpoint, custom templates are managed by the v2 API and
+// system templates by the v3 API.
+func buildStructTemplateUrl(cfg *config.Config, templateType string) string {
+	version := "v3/"
+	if templateType == "custom" {
+		version = "v2/"
+	}
+	return strings.Replace(cfg.Endpoints["lts"], "https//", "https://", -1) + version +
+		cfg.HwClient.ProjectID + "/lts/struct/template"
+}
 
-// func buildTagFieldsInfo(rawDimensions []interface{}) []entity.TagFieldsInfo {
-// 	if len(rawDimensions) == 0 {
-// 		return nil
-// 	}
-// 	dimensions := make([]entity.TagFieldsInfo, len(rawDimensions))
-// 	for i, rawdimension := range rawDimensions {
-// 		dimension := rawdimension.(map[string]interface{})
-// 		dimensions[i] = entity.TagFieldsInfo{
-// 			FieldName:  dimension["field_name"].(string),
-// 			Type:       dimension["type"].(string),
-// 			Content:    dimension["content"].(*string),
-// 			IsAnalysis: dimension["is_analysis"].(*bool),
-// 		}
-// 	}
-// 	return dimensions
-// }
 func resourceLtsStructTemplateCreate(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
 	config := meta.(*config.Config)
 	client, diaErr := httpclient_go.NewHttpClientGo(config)
 	if diaErr != nil {
 		return diaErr
 	}
-	var url string
+	url := buildStructTemplateUrl(config, d.Get("template_type").(string))
 	header := make(map[string]string)
 	header["content-type"] = "application/json;charset=UTF8"
-	opts := entity.StructTemplateRequest{
-		LogGroupId:  d.Get("log_group_id").(string),
-		LogStreamId: d.Get("log_stream_id").(string),
-	}
-	if d.Get("template_type").(string) == "custom" {
-		url = strings.Replace(config.Endpoints["lts"], "https//", "https://", -1) + "v2/" + 
-	       config.HwClient.ProjectID + "/lts/struct/template"
-		opts.ToDemoFieldsInfo()
-		opts.ParseType = "split"
-		opts.Tokenizer = " "
-		opts.Content = "127.0.0.1 10.142.203.101 8080 [18/Aug/2021:15:14:33 +0800] GET /apm HTTP/1.1 404 86 6"
-	} else {
-		url = strings.Replace(config.Endpoints["lts"], "https//", "https://", -1) + "v3/" + 
-			config.HwClient.ProjectID + "/lts/struct/template"
-			opts.TemplateId = d.Get("template_id").(string)
-			opts.TemplateType = d.Get("template_type").(string)
-			opts.TemplateName = d.Get("template_name").(string)
+	if diags := resolveSystemStructTemplateId(d, config); diags != nil {
+		return diags
 	}
+	opts := buildStructTemplateRequest(d)
 	client.WithMethod(httpclient_go.MethodPost).WithUrl(url).WithHeader(header).WithBody(opts)
 	response, err := client.Do()
 	if err != nil {
@@ -232,22 +524,51 @@ func resourceLtsStructTemplateRead(_ context.Context, d *schema.ResourceData, me
 	header["content-type"] = "application/json;charset=UTF8"
 	client.WithMethod(httpclient_go.MethodGet).WithUrl(url).WithHeader(header)
 	resp, err := client.Do()
-	body, diag := client.CheckDeletedDiag(d, err, resp, "error StructTemplate read instance")
+	body, diags := client.CheckDeletedDiag(d, err, resp, "error StructTemplate read instance")
 	if body == nil {
-		return diag
+		return diags
 	}
-	body = body[1 : len(body)-1]
-	body2 := strings.Replace(string(body), `\\\`, "**", -1)
-	body3 := strings.Replace(body2, `\`, "", -1)
-	body4 := strings.Replace(body3, "**", `\`, -1)
 	rlt := &entity.ShowStructTemplateResponse{}
-	err = json.Unmarshal([]byte(body4), rlt)
+	if err := unmarshalEmbeddedJSON(body, rlt); err != nil {
+		return diag.Errorf("error decoding StructTemplate response %s: %s", string(body), err)
+	}
+	if rlt.Id == "" {
+		log.Printf("[WARN] no StructTemplate found for log stream %s, removing it from state", d.Get("log_stream_id").(string))
+		d.SetId("")
+		return nil
+	}
 	d.SetId(rlt.Id)
+	// template_type forces a new template, so an empty or differently spelled type returned by the API does not
+	// replace the configured one
+	templateType := d.Get("template_type").(string)
+	if rlt.TemplateType != "" && !strings.EqualFold(rlt.TemplateType, templateType) {
+		templateType = rlt.TemplateType
+	}
 	mErr := multierror.Append(nil,
+		d.Set("region", config.GetRegion(d)),
 		d.Set("demo_log", rlt.DemoLog),
 		d.Set("log_group_id", rlt.LogGroupId),
 		d.Set("log_stream_id", rlt.LogStreamId),
+		d.Set("template_id", rlt.TemplateId),
+		d.Set("template_name", rlt.TemplateName),
+		d.Set("template_type", templateType),
+		d.Set("demo_fields", flattenDemoFieldsInfo(rlt.DemoFields)),
+		d.Set("tag_fields", flattenTagFieldsInfo(rlt.TagFields)),
 	)
+	// the parse rules can only be configured for custom templates, the ones of system templates are not refreshed
+	if templateType == "custom" {
+		mErr = multierror.Append(mErr,
+			d.Set("content", rlt.DemoLog),
+			d.Set("parse_type", rlt.ParseType),
+			d.Set("tokenizer", rlt.Tokenizer),
+			d.Set("regex_rules", rlt.RegexRules),
+			d.Set("layers", rlt.Layers),
+			d.Set("log_format", rlt.LogFormat),
+		)
+		if fields, err := parseStructSample(buildStructParseRules(d.Get), rlt.DemoLog); err == nil {
+			mErr = multierror.Append(mErr, d.Set("parsed_fields", flattenParsedStructFields(fields)))
+		}
+	}
 	if err := mErr.ErrorOrNil(); err != nil {
 		return fmtp.DiagErrorf("error setting LtsStructTemplate fields: %w", err)
 	}
@@ -289,17 +610,15 @@ func resourceLtsStructTemplateUpdate(ctx context.Context, d *schema.ResourceData
 	if diaErr != nil {
 		return diaErr
 	}
-	url := strings.Replace(config.Endpoints["lts"], "https//", "https://", -1) + "v2/" + 
-	    config.HwClient.ProjectID + "/lts/struct/template"
+	url := buildStructTemplateUrl(config, d.Get("template_type").(string))
 	header := make(map[string]string)
 	header["content-type"] = "application/json;charset=UTF8"
-	structTemplateRequest := entity.StructTemplateRequest{
-		LogGroupId:   d.Get("log_group_id").(string),
-		LogStreamId:  d.Get("log_stream_id").(string),
-		TemplateId:   d.Get("template_id").(string),
-		TemplateType: d.Get("template_type").(string),
-		TemplateName: d.Get("template_name").(string),
+	if d.HasChange("template_name") {
+		if diags := resolveSystemStructTemplateId(d, config); diags != nil {
+			return diags
+		}
 	}
+	structTemplateRequest := buildStructTemplateRequest(d)
 	client.WithMethod(httpclient_go.MethodPut).WithUrl(url).WithHeader(header).WithBody(structTemplateRequest)
 	response, err := client.Do()
 	if err != nil {
@@ -311,11 +630,23 @@ func resourceLtsStructTemplateUpdate(ctx context.Context, d *schema.ResourceData
 	if err != nil {
 		return diag.Errorf("error convert data %s , %s", string(body), err)
 	}
-	if response.StatusCode == 201 {
-		rlt := &entity.DeleteStructTemplateReqBody{}
-		err = json.Unmarshal(body, rlt)
-		d.SetId(rlt.Id)
-		return nil
+	if response.StatusCode == 200 || response.StatusCode == 201 {
+		return resourceLtsStructTemplateRead(ctx, d, meta)
 	}
-	return diag.Errorf("error update StructTemplate fields %s: %s", structTemplateRequest, err)
+	return diag.Errorf("error update StructTemplate fields %s: %s", structTemplateRequest, string(body))
 }
+
+// resourceLtsStructTemplateImportState imports a struct template by the log stream it is bound to, the import ID
+// format is <log_group_id>/<log_stream_id>.
+func resourceLtsStructTemplateImportState(_ context.Context, d *schema.ResourceData,
+	_ interface{}) ([]*schema.ResourceData, error) {
+	parts := strings.Split(d.Id(), "/")
+	if len(parts) != 2 || parts[0] == "" || parts[1] == "" {
+		return nil, fmt.Errorf("invalid format specified for import ID, must be <log_group_id>/<log_stream_id>")
+	}
+	mErr := multierror.Append(nil,
+		d.Set("log_group_id", parts[0]),
+		d.Set("log_stream_id", parts[1]),
+	)
+	return []*schema.ResourceData{d}, mErr.ErrorOrNil()
+}
\ No newline at end of file
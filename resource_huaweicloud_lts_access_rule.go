@@ -3,14 +3,21 @@ package lts
 import (
 	"context"
 	"encoding/json"
+	"fmt"
+	"github.com/hashicorp/go-cty/cty"
 	"github.com/hashicorp/go-multierror"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/validation"
 	"github.com/huaweicloud/terraform-provider-huaweicloud/huaweicloud/config"
 	"github.com/huaweicloud/terraform-provider-huaweicloud/huaweicloud/services/internal/entity"
 	"github.com/huaweicloud/terraform-provider-huaweicloud/huaweicloud/services/internal/httpclient_go"
 	"github.com/huaweicloud/terraform-provider-huaweicloud/huaweicloud/utils"
 	"io/ioutil"
+	"log"
+	"regexp"
+	"strconv"
+	"strings"
 )
 
 func ResourceAomMappingRule() *schema.Resource {
@@ -20,17 +27,28 @@ func ResourceAomMappingRule() *schema.Resource {
 		DeleteContext:   resourceAomMappingRuleDelete,
 		UpdateContext:   resourceAomMappingRuleUpdate,
 		Importer: &schema.ResourceImporter{
-			StateContext: schema.ImportStatePassthroughContext,
+			StateContext: resourceAomMappingRuleImportState,
 		},
+
+		CustomizeDiff: resourceAomMappingRuleCustomizeDiff,
+
 		Schema: map[string]*schema.Schema{
 			"region": {
 				Type:     schema.TypeString,
 				Optional: true,
 				Computed: true,
+				ForceNew: true,
 			},
 			"is_batch": {
-				Type:     schema.TypeString,
-				Required: true,
+				Type:     schema.TypeBool,
+				Optional: true,
+				Default:  false,
+				ForceNew: true,
+			},
+			"rule_ids": {
+				Type:     schema.TypeList,
+				Computed: true,
+				Elem:     &schema.Schema{Type: schema.TypeString},
 			},
 			"rule_name": {
 				Type:     schema.TypeString,
@@ -50,21 +68,73 @@ func ResourceAomMappingRule() *schema.Resource {
 			},
 			"container_name": {
 				Type:     schema.TypeString,
+				Optional: true,
 				Computed: true,
 			},
 			"deployments": {
 				Type:     schema.TypeList,
-				Required: true,
+				Optional: true,
+				Elem:     &schema.Schema{Type: schema.TypeString},
+			},
+			"workloads": {
+				Type:     schema.TypeList,
+				Optional: true,
+				Elem: &schema.Resource{
+					Schema: map[string]*schema.Schema{
+						"kind": {
+							Type:         schema.TypeString,
+							Required:     true,
+							ValidateFunc: validation.StringInSlice(aomMappingWorkloadKinds, false),
+						},
+						"name": {
+							Type:     schema.TypeString,
+							Required: true,
+						},
+					},
+				},
+			},
+			"label_selector": {
+				Type:         schema.TypeMap,
+				Optional:     true,
+				Elem:         &schema.Schema{Type: schema.TypeString},
+				ValidateFunc: validateAomMappingLabelSelector,
+			},
+			"all_workloads": {
+				Type:     schema.TypeBool,
+				Optional: true,
+			},
+			"create_missing_targets": {
+				Type:     schema.TypeBool,
+				Optional: true,
+				Default:  false,
+			},
+			"target_ttl_in_days": {
+				Type:         schema.TypeInt,
+				Optional:     true,
+				Default:      7,
+				ValidateFunc: validation.IntBetween(1, 365),
+			},
+			"created_log_groups": {
+				Type:     schema.TypeList,
+				Computed: true,
+				Elem:     &schema.Schema{Type: schema.TypeString},
+			},
+			"created_log_streams": {
+				Type:     schema.TypeList,
+				Computed: true,
 				Elem:     &schema.Schema{Type: schema.TypeString},
 			},
 			"files": {
 				Type:     schema.TypeList,
-				Required: true,
+				Optional: true,
+				Computed: true,
+				MaxItems: maxContainerLogPaths,
 				Elem: &schema.Resource{
 					Schema:map[string]*schema.Schema{
 						"file_name": {
-							Type:     schema.TypeString,
-							Required: true,
+							Type:         schema.TypeString,
+							Required:     true,
+							ValidateFunc: validateContainerLogPath,
 						},
 						"log_stream_info": {
 							Type:     schema.TypeList,
@@ -72,20 +142,24 @@ func ResourceAomMappingRule() *schema.Resource {
 							Elem: &schema.Resource{
 								Schema:map[string]*schema.Schema{
 									"target_log_group_id": {
-										Type: schema.TypeString,
-										Required:true,
+										Type:     schema.TypeString,
+										Optional: true,
+										Computed: true,
 									},
 									"target_log_group_name": {
-										Type: schema.TypeString,
-										Required:true,
+										Type:     schema.TypeString,
+										Optional: true,
+										Computed: true,
 									},
 									"target_log_stream_id": {
-										Type: schema.TypeString,
-										Required:true,
+										Type:     schema.TypeString,
+										Optional: true,
+										Computed: true,
 									},
 									"target_log_stream_name": {
-										Type: schema.TypeString,
-										Required:true,
+										Type:     schema.TypeString,
+										Optional: true,
+										Computed: true,
 									},
 								},
 							},
@@ -96,47 +170,620 @@ func ResourceAomMappingRule() *schema.Resource {
 		},
 	}
 }
-func buildLogStreamOpts(rawRule []interface{}) entity.AomMappingLogStreamInfo {
-	s := rawRule[0].(map[string]interface{})
-	rst := entity.AomMappingLogStreamInfo{
-		TargetLogGroupId:    s["target_log_group_id"].(string),
-		TargetLogGroupName:  s["target_log_group_name"].(string),
-		TargetLogStreamId:   s["target_log_stream_id"].(string),
-		TargetLogStreamName: s["target_log_stream_name"].(string),
+// aomMappingWorkloadKinds are the workload kinds a mapping rule can select.
+var aomMappingWorkloadKinds = []string{"Deployment", "StatefulSet", "DaemonSet", "CronJob", "Job", "Pod"}
+
+var aomMappingWorkloadSelectors = []string{"deployments", "workloads", "label_selector", "all_workloads"}
+
+// selectsWorkloads reports whether a configured workload selector selects any workload. An explicit
+// all_workloads = false or an empty list does not, unknown values are assumed to.
+func selectsWorkloads(v cty.Value) bool {
+	if v.IsNull() {
+		return false
+	}
+	if !v.IsKnown() {
+		return true
+	}
+	if v.Type() == cty.Bool {
+		return v.True()
+	}
+	return v.LengthInt() > 0
+}
+
+// checkAomMappingWorkloadSelectors checks that a rule selects its workloads one way or another, all_workloads can
+// not be combined with the other selectors.
+func checkAomMappingWorkloadSelectors(d *schema.ResourceDiff) error {
+	rawConfig := d.GetRawConfig()
+	selected := make([]string, 0, len(aomMappingWorkloadSelectors))
+	for _, key := range aomMappingWorkloadSelectors {
+		if selectsWorkloads(rawConfig.GetAttr(key)) {
+			selected = append(selected, key)
+		}
+	}
+	if len(selected) == 0 {
+		return fmt.Errorf("one of %s must select the workloads of the rule",
+			strings.Join(aomMappingWorkloadSelectors, ", "))
+	}
+	if len(selected) > 1 && selectsWorkloads(rawConfig.GetAttr("all_workloads")) {
+		return fmt.Errorf("all_workloads can not be combined with %s", strings.Join(selected[:len(selected)-1], ", "))
+	}
+	return nil
+}
+
+var (
+	labelKeyRegexp   = regexp.MustCompile(`^([a-z0-9]([-a-z0-9]*[a-z0-9])?(\.[a-z0-9]([-a-z0-9]*[a-z0-9])?)*/)?[A-Za-z0-9]([-A-Za-z0-9_.]{0,61}[A-Za-z0-9])?$`)
+	labelValueRegexp = regexp.MustCompile(`^([A-Za-z0-9]([-A-Za-z0-9_.]{0,61}[A-Za-z0-9])?)?$`)
+)
+
+// aomMappingWorkload selects a workload by its kind and name.
+type aomMappingWorkload struct {
+	Kind string `json:"kind"`
+	Name string `json:"name"`
+}
+
+// aomMappingRuleInfo extends entity.AomMappingRuleInfo with the selectors for workloads other than deployments.
+type aomMappingRuleInfo struct {
+	entity.AomMappingRuleInfo
+	Workloads     []aomMappingWorkload `json:"workloads,omitempty"`
+	LabelSelector map[string]string    `json:"label_selector,omitempty"`
+	AllWorkloads  bool                 `json:"all_workloads,omitempty"`
+}
+
+// aomMappingRuleRequest is entity.AomMappingRequestInfo carrying the extended rule info.
+type aomMappingRuleRequest struct {
+	entity.AomMappingRequestInfo
+	RuleInfo aomMappingRuleInfo `json:"rule_info"`
+}
+
+func validateAomMappingLabelSelector(v interface{}, k string) ([]string, []error) {
+	var errs []error
+	for key, value := range v.(map[string]interface{}) {
+		if !labelKeyRegexp.MatchString(key) {
+			errs = append(errs, fmt.Errorf("%q contains an invalid label key %q", k, key))
+		}
+		if !labelValueRegexp.MatchString(value.(string)) {
+			errs = append(errs, fmt.Errorf("%q contains an invalid value %q for label %q", k, value, key))
+		}
+	}
+	return nil, errs
+}
+
+// The limits of the container log paths which the collector accepts.
+const (
+	maxContainerLogPaths      = 20
+	maxContainerLogPathLength = 512
+	maxContainerLogPathDepth  = 20
+)
+
+// The file names which collect the standard output and standard error of the containers.
+const (
+	containerStdoutPath = "stdout"
+	containerStderrPath = "stderr"
+)
+
+// containerStdioPseudoPaths are paths that refer to the standard streams inside a container, the collector can not
+// read them as files.
+var containerStdioPseudoPaths = map[string]string{
+	"/dev/stdout":     containerStdoutPath,
+	"/dev/stderr":     containerStderrPath,
+	"/dev/fd/1":       containerStdoutPath,
+	"/dev/fd/2":       containerStderrPath,
+	"/proc/self/fd/1": containerStdoutPath,
+	"/proc/self/fd/2": containerStderrPath,
+}
+
+// validateContainerLogPath checks a container log path against the glob rules of the collector: the path is
+// absolute, "*" matches within a directory or file name, "**" matches any number of directories and must be a whole
+// directory name, and the first directory and the file name can not be "**".
+func validateContainerLogPath(v interface{}, k string) ([]string, []error) {
+	path := v.(string)
+	if path == containerStdoutPath || path == containerStderrPath {
+		return nil, nil
+	}
+	if stream, ok := containerStdioPseudoPaths[path]; ok {
+		return nil, []error{fmt.Errorf("%q can not collect %s as a file, use %q instead", k, path, stream)}
+	}
+	if !strings.HasPrefix(path, "/") {
+		return nil, []error{fmt.Errorf("%q must be an absolute path, %q or %q, got %q", k,
+			containerStdoutPath, containerStderrPath, path)}
+	}
+	if len(path) > maxContainerLogPathLength {
+		return nil, []error{fmt.Errorf("%q can not be longer than %d characters", k, maxContainerLogPathLength)}
+	}
+	if strings.ContainsAny(path, "?[]{}") {
+		return nil, []error{fmt.Errorf("%q only supports the * and ** wildcards, got %q", k, path)}
+	}
+
+	segments := strings.Split(path[1:], "/")
+	if len(segments) > maxContainerLogPathDepth {
+		return nil, []error{fmt.Errorf("%q can not be deeper than %d levels, got %q", k, maxContainerLogPathDepth,
+			path)}
+	}
+	doubleStars := 0
+	for i, segment := range segments {
+		switch {
+		case segment == "" || segment == "." || segment == "..":
+			return nil, []error{fmt.Errorf("%q must be a clean path to a file, got %q", k, path)}
+		case segment == "**":
+			doubleStars++
+		case strings.Contains(segment, "**"):
+			return nil, []error{fmt.Errorf("%q can only use ** as a whole directory name, got %q", k, path)}
+		}
+		if i == 0 && len(segments) > 1 && strings.Contains(segment, "*") {
+			return nil, []error{fmt.Errorf("%q can not use wildcards in the first directory, got %q", k, path)}
+		}
+	}
+	if doubleStars > 1 {
+		return nil, []error{fmt.Errorf("%q can only use ** once, got %q", k, path)}
+	}
+	if segments[len(segments)-1] == "**" {
+		return nil, []error{fmt.Errorf("%q must end with a file name, got %q", k, path)}
+	}
+	return nil, nil
+}
+
+func buildWorkloadOpts(rawWorkloads []interface{}) []aomMappingWorkload {
+	workloads := make([]aomMappingWorkload, len(rawWorkloads))
+	for i, v := range rawWorkloads {
+		rawWorkload := v.(map[string]interface{})
+		workloads[i] = aomMappingWorkload{
+			Kind: rawWorkload["kind"].(string),
+			Name: rawWorkload["name"].(string),
+		}
+	}
+	return workloads
+}
+
+func flattenWorkloadOpts(workloads []aomMappingWorkload) []map[string]interface{} {
+	rst := make([]map[string]interface{}, len(workloads))
+	for i, workload := range workloads {
+		rst[i] = map[string]interface{}{
+			"kind": workload.Kind,
+			"name": workload.Name,
+		}
 	}
 	return rst
 }
 
-func buildFileOpts(rawRules []interface{}) []entity.AomMappingfilesInfo {
-	file := make([]entity.AomMappingfilesInfo, len(rawRules))
-	for i, v := range rawRules {
-		rawRule := v.(map[string]interface{})
-		file[i].FileName = rawRule["file_name"].(string)
-		file[i].LogStreamInfo = buildLogStreamOpts(rawRule["log_stream_info"].([]interface{}))
+// buildAomMappingRuleInfo builds the rule info from the schema, the deployments are passed separately because each
+// rule of a batch is bound to its own deployment. The files are built from the configuration, since files is
+// computed and its planned value is unknown when a target changes.
+func buildAomMappingRuleInfo(d *schema.ResourceData, deployments []string) aomMappingRuleInfo {
+	return aomMappingRuleInfo{
+		AomMappingRuleInfo: entity.AomMappingRuleInfo{
+			ClusterId:     d.Get("cluster_id").(string),
+			ClusterName:   d.Get("cluster_name").(string),
+			Namespace:     d.Get("name_space").(string),
+			ContainerName: d.Get("container_name").(string),
+			Deployments:   deployments,
+			Files:         buildFileOpts(d.GetRawConfig().GetAttr("files")),
+		},
+		Workloads:     buildWorkloadOpts(d.Get("workloads").([]interface{})),
+		LabelSelector: utils.ExpandToStringMap(d.Get("label_selector").(map[string]interface{})),
+		AllWorkloads:  d.Get("all_workloads").(bool),
+	}
+}
+
+// buildLogStreamOpts converts the configured targets of a file, the IDs and names which are not configured are left
+// empty and resolved from the configured ones.
+func buildLogStreamOpts(rawTargets cty.Value) []entity.AomMappingLogStreamInfo {
+	rst := make([]entity.AomMappingLogStreamInfo, 0)
+	if !rawTargets.IsKnown() || rawTargets.IsNull() {
+		return rst
+	}
+	for it := rawTargets.ElementIterator(); it.Next(); {
+		_, target := it.Element()
+		groupId, _ := configuredString(target.GetAttr("target_log_group_id"))
+		groupName, _ := configuredString(target.GetAttr("target_log_group_name"))
+		streamId, _ := configuredString(target.GetAttr("target_log_stream_id"))
+		streamName, _ := configuredString(target.GetAttr("target_log_stream_name"))
+		rst = append(rst, entity.AomMappingLogStreamInfo{
+			TargetLogGroupId:    groupId,
+			TargetLogGroupName:  groupName,
+			TargetLogStreamId:   streamId,
+			TargetLogStreamName: streamName,
+		})
+	}
+	return rst
+}
+
+// buildFileOpts converts the configured files into the API shape, which holds a single target per file. A file
+// with several targets is sent once per target.
+func buildFileOpts(rawFiles cty.Value) []entity.AomMappingfilesInfo {
+	file := make([]entity.AomMappingfilesInfo, 0)
+	if !rawFiles.IsKnown() || rawFiles.IsNull() {
+		return file
+	}
+	for it := rawFiles.ElementIterator(); it.Next(); {
+		_, rawFile := it.Element()
+		fileName, _ := configuredString(rawFile.GetAttr("file_name"))
+		for _, target := range buildLogStreamOpts(rawFile.GetAttr("log_stream_info")) {
+			file = append(file, entity.AomMappingfilesInfo{
+				FileName:      fileName,
+				LogStreamInfo: target,
+			})
+		}
 	}
 	return file
 }
 
+// flattenFileOpts groups the targets returned by the API by file, in the order the files are returned.
+func flattenFileOpts(files []entity.AomMappingfilesInfo) []map[string]interface{} {
+	rst := make([]map[string]interface{}, 0, len(files))
+	indexes := make(map[string]int)
+	for _, file := range files {
+		target := map[string]interface{}{
+			"target_log_group_id":    file.LogStreamInfo.TargetLogGroupId,
+			"target_log_group_name":  file.LogStreamInfo.TargetLogGroupName,
+			"target_log_stream_id":   file.LogStreamInfo.TargetLogStreamId,
+			"target_log_stream_name": file.LogStreamInfo.TargetLogStreamName,
+		}
+		if i, ok := indexes[file.FileName]; ok {
+			rst[i]["log_stream_info"] = append(rst[i]["log_stream_info"].([]map[string]interface{}), target)
+			continue
+		}
+		indexes[file.FileName] = len(rst)
+		rst = append(rst, map[string]interface{}{
+			"file_name":       file.FileName,
+			"log_stream_info": []map[string]interface{}{target},
+		})
+	}
+	return rst
+}
+
+// aomMappingRuleIds returns the IDs of all rules managed by the resource, batch mode creates one rule per deployment.
+func aomMappingRuleIds(d *schema.ResourceData) []string {
+	ruleIds := utils.ExpandToStringList(d.Get("rule_ids").([]interface{}))
+	if len(ruleIds) == 0 {
+		return []string{d.Id()}
+	}
+	return ruleIds
+}
+
+// getAomMappingRule queries a mapping rule by ID, a nil rule is returned if it does not exist.
+func getAomMappingRule(cfg *config.Config, region, ruleId string) (*aomMappingRuleRequest, diag.Diagnostics) {
+	client, diaErr := httpclient_go.NewHttpClientGo(cfg)
+	if diaErr != nil {
+		return nil, diaErr
+	}
+	header := make(map[string]string)
+	header["content-type"] = "application/json;charset=UTF8"
+	client.WithMethod(httpclient_go.MethodGet).
+		WithUrlWithoutEndpoint(cfg, "lts", region, "v2/"+cfg.GetProjectID(region)+"/lts/aom-mapping/"+ruleId).
+		WithHeader(header)
+	response, err := client.Do()
+	if err != nil {
+		return nil, diag.Errorf("error retrieving AomMappingRule %s: %s", ruleId, err)
+	}
+	defer response.Body.Close()
+	body, err := ioutil.ReadAll(response.Body)
+	if err != nil {
+		return nil, diag.Errorf("error convert data %s, %s", string(body), err)
+	}
+	if response.StatusCode == 404 {
+		return nil, nil
+	}
+	if response.StatusCode != 200 {
+		return nil, diag.Errorf("error retrieving AomMappingRule %s: %s", ruleId, string(body))
+	}
+	rlt := make([]aomMappingRuleRequest, 0)
+	if err := json.Unmarshal(body, &rlt); err != nil {
+		return nil, diag.Errorf("error convert data %s, %s", string(body), err)
+	}
+	if len(rlt) == 0 {
+		return nil, nil
+	}
+	return &rlt[0], nil
+}
+
+// configuredString returns the configured value of a string attribute, unknown values are returned as empty strings.
+func configuredString(v cty.Value) (string, bool) {
+	if v.IsNull() {
+		return "", false
+	}
+	if !v.IsKnown() {
+		return "", true
+	}
+	return v.AsString(), true
+}
+
+// checkAomMappingTargets checks that each target names its log group and log stream, and that the names match the
+// IDs when both are configured. The streams of a group given by name are checked once the group is found, a group
+// which does not exist yet may be created on apply.
+func checkAomMappingTargets(d *schema.ResourceDiff, cfg *config.Config) error {
+	files := d.GetRawConfig().GetAttr("files")
+	if !files.IsKnown() || files.IsNull() {
+		return nil
+	}
+	region := d.Get("region").(string)
+	if region == "" {
+		region = cfg.Region
+	}
+	resolver := newLogTargetResolver(cfg, region)
+	for fileIt := files.ElementIterator(); fileIt.Next(); {
+		_, file := fileIt.Element()
+		targets := file.GetAttr("log_stream_info")
+		if !targets.IsKnown() || targets.IsNull() {
+			continue
+		}
+		for targetIt := targets.ElementIterator(); targetIt.Next(); {
+			_, target := targetIt.Element()
+			groupId, groupIdSet := configuredString(target.GetAttr("target_log_group_id"))
+			groupName, groupNameSet := configuredString(target.GetAttr("target_log_group_name"))
+			streamId, streamIdSet := configuredString(target.GetAttr("target_log_stream_id"))
+			streamName, streamNameSet := configuredString(target.GetAttr("target_log_stream_name"))
+			if !groupIdSet && !groupNameSet {
+				return fmt.Errorf("either target_log_group_id or target_log_group_name must be specified")
+			}
+			if !streamIdSet && !streamNameSet {
+				return fmt.Errorf("either target_log_stream_id or target_log_stream_name must be specified")
+			}
+			checkGroup := groupId != "" && groupName != ""
+			checkStream := streamId != "" && streamName != ""
+			if (!checkGroup && !checkStream) || (groupId == "" && groupName == "") {
+				continue
+			}
+
+			group, diags := resolver.logGroup(groupId, groupName)
+			if diags.HasError() {
+				return fmt.Errorf("%s: %s", diags[0].Summary, diags[0].Detail)
+			}
+			if group == nil && groupId != "" {
+				return fmt.Errorf("the log group %s does not exist", groupId)
+			}
+			if group == nil {
+				continue
+			}
+			if groupName != "" && group.LogGroupName != groupName {
+				return fmt.Errorf("target_log_group_name %q does not match the name %q of log group %s",
+					groupName, group.LogGroupName, groupId)
+			}
+			if !checkStream {
+				continue
+			}
+			stream, diags := resolver.logStream(group.LogGroupId, streamId, "")
+			if diags.HasError() {
+				return fmt.Errorf("%s: %s", diags[0].Summary, diags[0].Detail)
+			}
+			if stream == nil {
+				return fmt.Errorf("the log stream %s does not exist in log group %s", streamId, group.LogGroupId)
+			}
+			if stream.LogStreamName != streamName {
+				return fmt.Errorf("target_log_stream_name %q does not match the name %q of log stream %s",
+					streamName, stream.LogStreamName, streamId)
+			}
+		}
+	}
+	return nil
+}
+
+// aomMappingTargetPairs are the target attributes which are resolved from each other, an ID from its name and a
+// name from its ID.
+var aomMappingTargetPairs = [][2]string{
+	{"target_log_group_id", "target_log_group_name"},
+	{"target_log_stream_id", "target_log_stream_name"},
+}
+
+// markChangedTargetsComputed marks the files as known after apply when a target configured by only its ID or only
+// its name changes, since the other one is resolved on apply and the value in the state is stale. The nested target
+// attributes can not be marked on their own.
+func markChangedTargetsComputed(d *schema.ResourceDiff) error {
+	rawFiles := d.GetRawConfig().GetAttr("files")
+	if d.Id() == "" || !rawFiles.IsKnown() || rawFiles.IsNull() {
+		return nil
+	}
+	oldFiles, _ := d.GetChange("files")
+	old := oldFiles.([]interface{})
+	i := 0
+	for fileIt := rawFiles.ElementIterator(); fileIt.Next(); i++ {
+		_, file := fileIt.Element()
+		targets := file.GetAttr("log_stream_info")
+		if !targets.IsKnown() || targets.IsNull() {
+			return d.SetNewComputed("files")
+		}
+		var oldTargets []interface{}
+		if i < len(old) && old[i] != nil {
+			oldTargets = old[i].(map[string]interface{})["log_stream_info"].([]interface{})
+		}
+		j := 0
+		for targetIt := targets.ElementIterator(); targetIt.Next(); j++ {
+			_, target := targetIt.Element()
+			if j >= len(oldTargets) || oldTargets[j] == nil {
+				return d.SetNewComputed("files")
+			}
+			oldTarget := oldTargets[j].(map[string]interface{})
+			for _, pair := range aomMappingTargetPairs {
+				for k, key := range pair {
+					if !target.GetAttr(pair[1-k]).IsNull() {
+						continue
+					}
+					value, configured := configuredString(target.GetAttr(key))
+					if configured && (value == "" || value != oldTarget[key].(string)) {
+						return d.SetNewComputed("files")
+					}
+				}
+			}
+		}
+	}
+	return nil
+}
+
+// resolveAomMappingTargets fills in the missing IDs and names of the target log groups and log streams. Targets
+// given only by name are created when the resolver is allowed to.
+func resolveAomMappingTargets(resolver *logTargetResolver, files []entity.AomMappingfilesInfo) diag.Diagnostics {
+	for i := range files {
+		target := &files[i].LogStreamInfo
+		group, diags := resolver.logGroup(target.TargetLogGroupId, target.TargetLogGroupName)
+		if diags != nil {
+			return diags
+		}
+		if group == nil && resolver.createMissing && target.TargetLogGroupId == "" {
+			if group, diags = resolver.createLogGroup(target.TargetLogGroupName); diags != nil {
+				return diags
+			}
+		}
+		if group == nil {
+			return diag.Errorf("unable to find the log group %s%s", target.TargetLogGroupId, target.TargetLogGroupName)
+		}
+		target.TargetLogGroupId = group.LogGroupId
+		target.TargetLogGroupName = group.LogGroupName
+
+		stream, diags := resolver.logStream(group.LogGroupId, target.TargetLogStreamId, target.TargetLogStreamName)
+		if diags != nil {
+			return diags
+		}
+		if stream == nil && resolver.createMissing && target.TargetLogStreamId == "" {
+			if stream, diags = resolver.createLogStream(group.LogGroupId, target.TargetLogStreamName); diags != nil {
+				return diags
+			}
+		}
+		if stream == nil {
+			return diag.Errorf("unable to find the log stream %s%s in log group %s", target.TargetLogStreamId,
+				target.TargetLogStreamName, group.LogGroupId)
+		}
+		target.TargetLogStreamId = stream.LogStreamId
+		target.TargetLogStreamName = stream.LogStreamName
+	}
+	return nil
+}
+
+// newAomMappingTargetResolver returns a resolver which creates the missing targets if create_missing_targets is set.
+func newAomMappingTargetResolver(cfg *config.Config, d *schema.ResourceData) *logTargetResolver {
+	resolver := newLogTargetResolver(cfg, cfg.GetRegion(d))
+	resolver.createMissing = d.Get("create_missing_targets").(bool)
+	resolver.ttlInDays = d.Get("target_ttl_in_days").(int)
+	return resolver
+}
+
+// saveCreatedTargets adds the targets created by the resolver to the ones already owned by the resource, they are
+// deleted together with the resource.
+func saveCreatedTargets(d *schema.ResourceData, resolver *logTargetResolver) error {
+	groups := append(utils.ExpandToStringList(d.Get("created_log_groups").([]interface{})), resolver.createdGroups...)
+	streams := append(utils.ExpandToStringList(d.Get("created_log_streams").([]interface{})),
+		resolver.createdStreams...)
+	mErr := multierror.Append(nil,
+		d.Set("created_log_groups", groups),
+		d.Set("created_log_streams", streams),
+	)
+	return mErr.ErrorOrNil()
+}
+
+// discardCreatedTargets deletes the targets created by the resolver for an operation which failed before any rule
+// used them, they are not tracked by any state.
+func discardCreatedTargets(cfg *config.Config, region string, resolver *logTargetResolver) {
+	if len(resolver.createdStreams)+len(resolver.createdGroups) == 0 {
+		return
+	}
+	if diags := deleteCreatedLogTargets(cfg, region, resolver.createdGroups, resolver.createdStreams); diags != nil {
+		log.Printf("[WARN] error cleaning up the log targets created for AomMappingRule: %s", diags[0].Summary)
+	}
+}
+
+// releaseUnusedTargets deletes the log groups and log streams created by the resource which none of the resolved
+// files target anymore. The targets stay owned by the resource when they can not be deleted.
+func releaseUnusedTargets(cfg *config.Config, d *schema.ResourceData,
+	files []entity.AomMappingfilesInfo) diag.Diagnostics {
+	usedGroups := make(map[string]bool)
+	usedStreams := make(map[string]bool)
+	for _, file := range files {
+		usedGroups[file.LogStreamInfo.TargetLogGroupId] = true
+		usedStreams[file.LogStreamInfo.TargetLogGroupId+"/"+file.LogStreamInfo.TargetLogStreamId] = true
+	}
+	split := func(key string, used map[string]bool) ([]string, []string) {
+		kept := make([]string, 0)
+		unused := make([]string, 0)
+		for _, target := range utils.ExpandToStringList(d.Get(key).([]interface{})) {
+			if used[target] {
+				kept = append(kept, target)
+			} else {
+				unused = append(unused, target)
+			}
+		}
+		return kept, unused
+	}
+	groups, unusedGroups := split("created_log_groups", usedGroups)
+	streams, unusedStreams := split("created_log_streams", usedStreams)
+	if len(unusedGroups)+len(unusedStreams) == 0 {
+		return nil
+	}
+	if diags := deleteCreatedLogTargets(cfg, cfg.GetRegion(d), unusedGroups, unusedStreams); diags != nil {
+		return diags
+	}
+	mErr := multierror.Append(nil,
+		d.Set("created_log_groups", groups),
+		d.Set("created_log_streams", streams),
+	)
+	if err := mErr.ErrorOrNil(); err != nil {
+		return diag.Errorf("error setting AomMappingRule created targets: %s", err)
+	}
+	return nil
+}
+
+// resourceAomMappingRuleCustomizeDiff checks the files and their targets, and replaces batch rules when the
+// deployments change, because each rule of a batch is bound to one deployment. Batch mode only applies to
+// deployments.
+func resourceAomMappingRuleCustomizeDiff(_ context.Context, d *schema.ResourceDiff, meta interface{}) error {
+	if d.GetRawConfig().GetAttr("files").IsNull() {
+		return fmt.Errorf("files is required")
+	}
+	fileNames := make(map[string]bool)
+	for _, v := range d.Get("files").([]interface{}) {
+		fileName := v.(map[string]interface{})["file_name"].(string)
+		if fileName != "" && fileNames[fileName] {
+			return fmt.Errorf("the file %s is specified more than once, add its targets to one files block", fileName)
+		}
+		fileNames[fileName] = true
+	}
+	if err := checkAomMappingWorkloadSelectors(d); err != nil {
+		return err
+	}
+	if err := checkAomMappingTargets(d, meta.(*config.Config)); err != nil {
+		return err
+	}
+	if err := markChangedTargetsComputed(d); err != nil {
+		return err
+	}
+	if !d.Get("is_batch").(bool) {
+		return nil
+	}
+	rawConfig := d.GetRawConfig()
+	for _, key := range []string{"workloads", "label_selector", "all_workloads"} {
+		if selectsWorkloads(rawConfig.GetAttr(key)) {
+			return fmt.Errorf("%s can not be used in batch mode, which creates one rule per deployment", key)
+		}
+	}
+	if d.Id() != "" && d.HasChange("deployments") {
+		return d.ForceNew("deployments")
+	}
+	return nil
+}
+
 func resourceAomMappingRuleCreate(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
 	cfg := meta.(*config.Config)
+	region := cfg.GetRegion(d)
 	client, diaErr := httpclient_go.NewHttpClientGo(cfg)
 	if diaErr != nil {
 		return diaErr
 	}
-	aomMappingRequestInfo := entity.AomMappingRequestInfo{
-		ProjectId: cfg.HwClient.ProjectID,
-		RuleName: d.Get("rule_name").(string),
-		RuleInfo: entity.AomMappingRuleInfo{
-			ClusterId:   d.Get("cluster_id").(string),
-			ClusterName: d.Get("cluster_name").(string),
-			Namespace:   d.Get("name_space").(string),
-			Deployments:  utils.ExpandToStringList(d.Get("deployments").([]interface{})),
-			Files:       buildFileOpts(d.Get("files").([]interface{})),
+	aomMappingRequestInfo := aomMappingRuleRequest{
+		AomMappingRequestInfo: entity.AomMappingRequestInfo{
+			ProjectId: cfg.GetProjectID(region),
+			RuleName:  d.Get("rule_name").(string),
 		},
+		RuleInfo: buildAomMappingRuleInfo(d, utils.ExpandToStringList(d.Get("deployments").([]interface{}))),
+	}
+	resolver := newAomMappingTargetResolver(cfg, d)
+	defer func() {
+		if d.Id() == "" {
+			discardCreatedTargets(cfg, region, resolver)
+		}
+	}()
+	if diags := resolveAomMappingTargets(resolver, aomMappingRequestInfo.RuleInfo.Files); diags != nil {
+		return diags
 	}
 	client.WithMethod(httpclient_go.MethodPost).
-		WithUrlWithoutEndpoint(cfg, "lts", cfg.GetRegion(d), 
-			"v2/"+cfg.HwClient.ProjectID+"/lts/aom-mapping"+"?isBatch="+d.GET("is_batch").(string)).
+		WithUrlWithoutEndpoint(cfg, "lts", region,
+			"v2/"+cfg.GetProjectID(region)+"/lts/aom-mapping"+"?isBatch="+strconv.FormatBool(d.Get("is_batch").(bool))).
 		WithBody(aomMappingRequestInfo)
 	response, err := client.Do()
 	if err != nil {
@@ -154,36 +801,65 @@ func resourceAomMappingRuleCreate(ctx context.Context, d *schema.ResourceData, m
 		if err != nil {
 			return diag.Errorf("error convert data %s , %s", string(body), err)
 		}
-		d.SetId(rlt[0].RuleId)
+		if len(rlt) == 0 {
+			return diag.Errorf("error creating AomMappingRule: no rule returned in %s", string(body))
+		}
+		ruleIds := make([]string, len(rlt))
+		for i, rule := range rlt {
+			ruleIds[i] = rule.RuleId
+		}
+		d.SetId(ruleIds[0])
+		if err := d.Set("rule_ids", ruleIds); err != nil {
+			return diag.Errorf("error setting AomMappingRule rule_ids: %s", err)
+		}
+		if err := saveCreatedTargets(d, resolver); err != nil {
+			return diag.Errorf("error setting AomMappingRule created targets: %s", err)
+		}
 		return resourceAomMappingRuleRead(ctx, d, meta)
 	}
 	return diag.Errorf("error AomMappingRule Response %s : %s", aomMappingRequestInfo, string(body))
 }
 
 func resourceAomMappingRuleRead(_ context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
-	config := meta.(*config.Config)
-	client, diaErr := httpclient_go.NewHttpClientGo(config)
-	if diaErr != nil {
-		return diaErr
+	cfg := meta.(*config.Config)
+	ruleIds := make([]string, 0)
+	deployments := make([]string, 0)
+	var rlt *aomMappingRuleRequest
+	for _, ruleId := range aomMappingRuleIds(d) {
+		rule, diags := getAomMappingRule(cfg, cfg.GetRegion(d), ruleId)
+		if diags != nil {
+			return diags
+		}
+		if rule == nil {
+			continue
+		}
+		if rlt == nil {
+			rlt = rule
+		}
+		ruleIds = append(ruleIds, ruleId)
+		deployments = append(deployments, rule.RuleInfo.Deployments...)
 	}
-	header := make(map[string]string)
-	header["content-type"] = "application/json;charset=UTF8"
-	client.WithMethod(httpclient_go.MethodGet).
-		WithUrlWithoutEndpoint(cfg, "lts", cfg.GetRegion(d), 
-			"v2/"+cfg.HwClient.ProjectID+"/lts/aom-mapping/"+d.Id()).WithHeader(header)
-	response, err := client.Do()
-	body, diags := client.CheckDeletedDiag(d, err, response, "error retrieving AomMappingRule")
-	if body == nil {
-		return diags
+	if rlt == nil {
+		log.Printf("[WARN] AomMappingRule %s not found, removing it from state", d.Id())
+		d.SetId("")
+		return nil
 	}
-	rlt := make([]entity.AomMappingRequestInfo, 0)
-	err = json.Unmarshal(body, &rlt)
+	// the API does not return the batch flag, a resource that owns several rules has been created in batch mode
+	isBatch := d.Get("is_batch").(bool) || len(ruleIds) > 1
 	mErr := multierror.Append(nil,
-		d.Set("rule_name", rlt[0].RuleName),
-		d.Set("cluster_id", rlt[0].RuleInfo.ClusterId),
-		d.Set("cluster_name", rlt[0].RuleInfo.ClusterName),
-		d.Set("container_name", rlt[0].RuleInfo.ContainerName),
-		d.Set("deployments", rlt[0].RuleInfo.Deployments),
+		d.Set("region", cfg.GetRegion(d)),
+		d.Set("is_batch", isBatch),
+		d.Set("rule_name", rlt.RuleName),
+		d.Set("cluster_id", rlt.RuleInfo.ClusterId),
+		d.Set("cluster_name", rlt.RuleInfo.ClusterName),
+		d.Set("name_space", rlt.RuleInfo.Namespace),
+		d.Set("container_name", rlt.RuleInfo.ContainerName),
+		d.Set("deployments", deployments),
+		d.Set("workloads", flattenWorkloadOpts(rlt.RuleInfo.Workloads)),
+		d.Set("label_selector", rlt.RuleInfo.LabelSelector),
+		d.Set("all_workloads", rlt.RuleInfo.AllWorkloads),
+		d.Set("files", flattenFileOpts(rlt.RuleInfo.Files)),
+		d.Set("rule_ids", ruleIds),
 	)
 	if err := mErr.ErrorOrNil(); err != nil {
 		return diag.Errorf("error setting AomMappingRule fields: %s", err)
@@ -192,59 +868,113 @@ func resourceAomMappingRuleRead(_ context.Context, d *schema.ResourceData, meta
 }
 
 func resourceAomMappingRuleDelete(_ context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
-	config := meta.(*config.Config)
-	client, diaErr := httpclient_go.NewHttpClientGo(config)
+	cfg := meta.(*config.Config)
+	for _, ruleId := range aomMappingRuleIds(d) {
+		if diags := deleteAomMappingRule(cfg, cfg.GetRegion(d), ruleId); diags != nil {
+			return diags
+		}
+	}
+	return deleteCreatedLogTargets(cfg, cfg.GetRegion(d),
+		utils.ExpandToStringList(d.Get("created_log_groups").([]interface{})),
+		utils.ExpandToStringList(d.Get("created_log_streams").([]interface{})))
+}
+
+func deleteAomMappingRule(cfg *config.Config, region, ruleId string) diag.Diagnostics {
+	client, diaErr := httpclient_go.NewHttpClientGo(cfg)
 	if diaErr != nil {
 		return diaErr
 	}
 	header := make(map[string]string)
 	header["content-type"] = "application/json;charset=UTF8"
 	client.WithMethod(httpclient_go.MethodDelete).
-		WithUrlWithoutEndpoint(cfg, "lts", cfg.GetRegion(d), 
-			"v2/"+cfg.HwClient.ProjectID+"/lts/aom-mapping?id="+d.Id()).WithHeader(header)
+		WithUrlWithoutEndpoint(cfg, "lts", region,
+			"v2/"+cfg.GetProjectID(region)+"/lts/aom-mapping?id="+ruleId).WithHeader(header)
 	response, err := client.Do()
 	if err != nil {
-		return diag.Errorf("error delete AomMappingRule %s: %s", d.Id(), err)
+		return diag.Errorf("error delete AomMappingRule %s: %s", ruleId, err)
 	}
 	defer response.Body.Close()
 	body, err := ioutil.ReadAll(response.Body)
 	if err != nil {
-		return diag.Errorf("error delete AomMappingRule %s: %s", d.Id(), err)
+		return diag.Errorf("error delete AomMappingRule %s: %s", ruleId, err)
 	}
-	if response.StatusCode == 200 {
+	if response.StatusCode == 200 || response.StatusCode == 404 {
 		return nil
 	}
-	return diag.Errorf("error delete AomMappingRule %s:  %s", d.Id(), string(body))
+	return diag.Errorf("error delete AomMappingRule %s:  %s", ruleId, string(body))
 }
 
+// resourceAomMappingRuleUpdate resolves the targets once and updates every rule of the resource with them. The
+// targets created for the update are removed again when no rule was updated, and the created targets which are no
+// longer used are deleted once all rules are updated.
 func resourceAomMappingRuleUpdate(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
-	config := meta.(*config.Config)
-	client, diaErr := httpclient_go.NewHttpClientGo(config)
+	cfg := meta.(*config.Config)
+	region := cfg.GetRegion(d)
+	files := buildFileOpts(d.GetRawConfig().GetAttr("files"))
+	resolver := newAomMappingTargetResolver(cfg, d)
+	if diags := resolveAomMappingTargets(resolver, files); diags != nil {
+		discardCreatedTargets(cfg, region, resolver)
+		return diags
+	}
+	for i, ruleId := range aomMappingRuleIds(d) {
+		diags := updateAomMappingRule(cfg, d, ruleId, files)
+		if diags == nil {
+			continue
+		}
+		if i == 0 {
+			discardCreatedTargets(cfg, region, resolver)
+		} else if err := saveCreatedTargets(d, resolver); err != nil {
+			log.Printf("[WARN] error setting AomMappingRule created targets: %s", err)
+		}
+		return diags
+	}
+	if err := saveCreatedTargets(d, resolver); err != nil {
+		return diag.Errorf("error setting AomMappingRule created targets: %s", err)
+	}
+	if diags := releaseUnusedTargets(cfg, d, files); diags != nil {
+		return diags
+	}
+	return resourceAomMappingRuleRead(ctx, d, meta)
+}
+
+// updateAomMappingRule updates a single rule with the resolved files, the rules of a batch keep their own
+// deployment.
+func updateAomMappingRule(cfg *config.Config, d *schema.ResourceData, ruleId string,
+	files []entity.AomMappingfilesInfo) diag.Diagnostics {
+	region := cfg.GetRegion(d)
+	deployments := utils.ExpandToStringList(d.Get("deployments").([]interface{}))
+	if d.Get("is_batch").(bool) {
+		rule, diags := getAomMappingRule(cfg, region, ruleId)
+		if diags != nil {
+			return diags
+		}
+		if rule == nil {
+			return diag.Errorf("error update AomMappingRule %s: the rule does not exist", ruleId)
+		}
+		deployments = rule.RuleInfo.Deployments
+	}
+	client, diaErr := httpclient_go.NewHttpClientGo(cfg)
 	if diaErr != nil {
 		return diaErr
 	}
-	url := strings.Replace(config.Endpoints["lts"], "https//", "https://", -1) + "v2/" + config.HwClient.ProjectID + 
-	       "/lts/aom-mapping"
-	Opts := entity.AomMappingRequestInfo{
-		ProjectId: config.HwClient.ProjectID,
-		RuleId:    d.Id(),
-		RuleName:  d.Get("rule_name").(string),
-		RuleInfo:  entity.AomMappingRuleInfo{
-			ClusterId:   d.Get("cluster_id").(string),
-			ClusterName: d.Get("cluster_name").(string),
-			Namespace:   d.Get("name_space").(string),
-			Deployments:  utils.ExpandToStringList(d.Get("deployments").([]interface{})),
-			Files:       buildFileOpts(d.Get("files").([]interface{})),
+	Opts := aomMappingRuleRequest{
+		AomMappingRequestInfo: entity.AomMappingRequestInfo{
+			ProjectId: cfg.GetProjectID(region),
+			RuleId:    ruleId,
+			RuleName:  d.Get("rule_name").(string),
 		},
+		RuleInfo: buildAomMappingRuleInfo(d, deployments),
 	}
-	client.WithMethod(httpclient_go.MethodPost).
-		WithUrlWithoutEndpoint(cfg, "lts", cfg.GetRegion(d), 
-			"v2/"+cfg.HwClient.ProjectID+"/lts/aom-mapping").WithBody(Opts)
+	Opts.RuleInfo.Files = files
+	header := make(map[string]string)
+	header["content-type"] = "application/json;charset=UTF8"
+	client.WithMethod(httpclient_go.MethodPut).
+		WithUrlWithoutEndpoint(cfg, "lts", region, "v2/"+cfg.GetProjectID(region)+"/lts/aom-mapping").
+		WithHeader(header).WithBody(Opts)
 	response, err := client.Do()
 	if err != nil {
 		return diag.Errorf("error update AomMappingRule fields %s: %s", Opts.RuleName, err)
 	}
-	d.SetId(Opts.RuleId)
 
 	defer response.Body.Close()
 	body, err := ioutil.ReadAll(response.Body)
@@ -254,5 +984,35 @@ func resourceAomMappingRuleUpdate(ctx context.Context, d *schema.ResourceData, m
 	if response.StatusCode == 200 {
 		return nil
 	}
-	return diag.Errorf("error update AomMappingRule %s:  %s", Opts, string(body))
+	return diag.Errorf("error update AomMappingRule %s:  %s", Opts.RuleId, string(body))
+}
+
+// resourceAomMappingRuleImportState imports a mapping rule by <cluster_id>/<rule_name>, the rule ID is resolved with
+// the list API. A plain rule ID is accepted as well.
+func resourceAomMappingRuleImportState(_ context.Context, d *schema.ResourceData,
+	meta interface{}) ([]*schema.ResourceData, error) {
+	parts := strings.SplitN(d.Id(), "/", 2)
+	if len(parts) == 1 {
+		return []*schema.ResourceData{d}, nil
+	}
+	if parts[0] == "" || parts[1] == "" {
+		return nil, fmt.Errorf("invalid format specified for import ID, must be <cluster_id>/<rule_name>")
+	}
+
+	cfg := meta.(*config.Config)
+	rules, diags := listAomMappingRules(cfg, cfg.GetRegion(d))
+	if diags.HasError() {
+		return nil, fmt.Errorf("%s: %s", diags[0].Summary, diags[0].Detail)
+	}
+	ruleIds := make([]string, 0)
+	for _, rule := range rules {
+		if rule.RuleInfo.ClusterId == parts[0] && rule.RuleName == parts[1] {
+			ruleIds = append(ruleIds, rule.RuleId)
+		}
+	}
+	if len(ruleIds) == 0 {
+		return nil, fmt.Errorf("unable to find the AomMappingRule %s in cluster %s", parts[1], parts[0])
+	}
+	d.SetId(ruleIds[0])
+	return []*schema.ResourceData{d}, d.Set("rule_ids", ruleIds)
 }
@@ -0,0 +1,179 @@
+package lts
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/hashicorp/go-cty/cty"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/terraform"
+	"github.com/huaweicloud/terraform-provider-huaweicloud/huaweicloud/services/internal/entity"
+)
+
+func TestValidateAomMappingLabelSelector(t *testing.T) {
+	cases := map[string]struct {
+		selector map[string]interface{}
+		errs     int
+	}{
+		"empty":                {selector: map[string]interface{}{}},
+		"plain label":          {selector: map[string]interface{}{"app": "nginx"}},
+		"prefixed label":       {selector: map[string]interface{}{"app.kubernetes.io/name": "nginx-1.25"}},
+		"empty value":          {selector: map[string]interface{}{"tier": ""}},
+		"value with dots":      {selector: map[string]interface{}{"version": "v1.2_rc"}},
+		"uppercase prefix":     {selector: map[string]interface{}{"Example.com/app": "nginx"}, errs: 1},
+		"empty name":           {selector: map[string]interface{}{"example.com/": "nginx"}, errs: 1},
+		"key with space":       {selector: map[string]interface{}{"my app": "nginx"}, errs: 1},
+		"value with slash":     {selector: map[string]interface{}{"app": "web/nginx"}, errs: 1},
+		"value ending in dash": {selector: map[string]interface{}{"app": "nginx-"}, errs: 1},
+		"too long name": {
+			selector: map[string]interface{}{"a234567890123456789012345678901234567890123456789012345678901234": "x"},
+			errs:     1,
+		},
+		"invalid key and value": {selector: map[string]interface{}{"-app": "nginx!"}, errs: 2},
+	}
+
+	for name, c := range cases {
+		_, errs := validateAomMappingLabelSelector(c.selector, "label_selector")
+		if len(errs) != c.errs {
+			t.Errorf("%s: got errors %v, want %d errors", name, errs, c.errs)
+		}
+	}
+}
+
+func TestSelectsWorkloads(t *testing.T) {
+	cases := []struct {
+		value cty.Value
+		want  bool
+	}{
+		{cty.NullVal(cty.Bool), false},
+		{cty.False, false},
+		{cty.True, true},
+		{cty.UnknownVal(cty.Bool), true},
+		{cty.ListValEmpty(cty.String), false},
+		{cty.ListVal([]cty.Value{cty.StringVal("nginx")}), true},
+		{cty.MapValEmpty(cty.String), false},
+		{cty.MapVal(map[string]cty.Value{"app": cty.StringVal("nginx")}), true},
+		{cty.UnknownVal(cty.List(cty.String)), true},
+	}
+
+	for _, c := range cases {
+		if got := selectsWorkloads(c.value); got != c.want {
+			t.Errorf("selectsWorkloads(%#v) = %t, want %t", c.value, got, c.want)
+		}
+	}
+}
+
+func TestValidateContainerLogPath(t *testing.T) {
+	valid := []string{
+		"stdout",
+		"stderr",
+		"/var/log/app.log",
+		"/var/log/*.log",
+		"/var/log/**/app.log",
+		"/var/log/app-*/*.log",
+		"/data/**/logs/*",
+		"/app.log",
+		"/" + strings.Repeat("a/", maxContainerLogPathDepth-1) + "app.log",
+	}
+	for _, path := range valid {
+		if _, errs := validateContainerLogPath(path, "file_name"); len(errs) > 0 {
+			t.Errorf("validateContainerLogPath(%q) returned %v, want no errors", path, errs)
+		}
+	}
+
+	// the invalid paths with a part of the expected error
+	invalid := map[string]string{
+		"/dev/stdout":          `use "stdout" instead`,
+		"/proc/self/fd/2":      `use "stderr" instead`,
+		"var/log/app.log":      "must be an absolute path",
+		"STDOUT":               "must be an absolute path",
+		"/var/log/app?.log":    "only supports the * and ** wildcards",
+		"/var/log/[ab].log":    "only supports the * and ** wildcards",
+		"/var/log/{a,b}.log":   "only supports the * and ** wildcards",
+		"/var//log/app.log":    "must be a clean path",
+		"/var/log/../app.log":  "must be a clean path",
+		"/var/log/":            "must be a clean path",
+		"/var/log/app**/a.log": "can only use ** as a whole directory name",
+		"/var/log/**.log":      "can only use ** as a whole directory name",
+		"/*/log/app.log":       "can not use wildcards in the first directory",
+		"/**/app.log":          "can not use wildcards in the first directory",
+		"/var/**/log/**/a.log": "can only use ** once",
+		"/var/log/**":          "must end with a file name",
+		"/" + strings.Repeat("a/", maxContainerLogPathDepth) + "app.log": "can not be deeper than",
+		"/" + strings.Repeat("a", maxContainerLogPathLength):             "can not be longer than",
+	}
+	for path, want := range invalid {
+		_, errs := validateContainerLogPath(path, "file_name")
+		if len(errs) != 1 || !strings.Contains(errs[0].Error(), want) {
+			t.Errorf("validateContainerLogPath(%q) returned %v, want an error containing %q", path, errs, want)
+		}
+	}
+}
+
+// testAomMappingTarget returns the configuration of a target, empty arguments are not configured.
+func testAomMappingTarget(groupId, groupName, streamId, streamName string) cty.Value {
+	attrs := make(map[string]cty.Value)
+	for key, value := range map[string]string{
+		"target_log_group_id":    groupId,
+		"target_log_group_name":  groupName,
+		"target_log_stream_id":   streamId,
+		"target_log_stream_name": streamName,
+	} {
+		attrs[key] = cty.NullVal(cty.String)
+		if value != "" {
+			attrs[key] = cty.StringVal(value)
+		}
+	}
+	return cty.ObjectVal(attrs)
+}
+
+// TestBuildAomMappingRuleInfoOnUpdate covers an update which adds a target to a file and moves the existing target
+// to another log group by name. files is computed, so the targets are built from the configuration and not from the
+// state, which still holds the resolved IDs of the previous target.
+func TestBuildAomMappingRuleInfoOnUpdate(t *testing.T) {
+	state := &terraform.InstanceState{
+		ID: "rule-1",
+		Attributes: map[string]string{
+			"id":                        "rule-1",
+			"files.#":                   "1",
+			"files.0.file_name":         "/var/log/app/*.log",
+			"files.0.log_stream_info.#": "1",
+			"files.0.log_stream_info.0.target_log_group_id":    "group-1",
+			"files.0.log_stream_info.0.target_log_group_name":  "apm",
+			"files.0.log_stream_info.0.target_log_stream_id":   "stream-1",
+			"files.0.log_stream_info.0.target_log_stream_name": "app",
+		},
+		RawConfig: cty.ObjectVal(map[string]cty.Value{
+			"files": cty.ListVal([]cty.Value{
+				cty.ObjectVal(map[string]cty.Value{
+					"file_name": cty.StringVal("/var/log/app/*.log"),
+					"log_stream_info": cty.ListVal([]cty.Value{
+						testAomMappingTarget("", "apm-prod", "", "app"),
+						testAomMappingTarget("group-2", "", "stream-2", ""),
+					}),
+				}),
+			}),
+		}),
+	}
+	d := ResourceAomMappingRule().Data(state)
+
+	want := []entity.AomMappingfilesInfo{
+		{
+			FileName: "/var/log/app/*.log",
+			LogStreamInfo: entity.AomMappingLogStreamInfo{
+				TargetLogGroupName:  "apm-prod",
+				TargetLogStreamName: "app",
+			},
+		},
+		{
+			FileName: "/var/log/app/*.log",
+			LogStreamInfo: entity.AomMappingLogStreamInfo{
+				TargetLogGroupId:  "group-2",
+				TargetLogStreamId: "stream-2",
+			},
+		},
+	}
+	if got := buildAomMappingRuleInfo(d, nil).Files; !reflect.DeepEqual(got, want) {
+		t.Fatalf("got files %+v, want %+v", got, want)
+	}
+}
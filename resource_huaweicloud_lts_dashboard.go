@@ -3,6 +3,7 @@ package lts
 import (
 	"context"
 	"encoding/json"
+	"fmt"
 	"github.com/hashicorp/go-multierror"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
 	"github.com/huaweicloud/terraform-provider-huaweicloud/huaweicloud/config"
@@ -11,11 +12,52 @@ import (
 	"github.com/huaweicloud/terraform-provider-huaweicloud/huaweicloud/utils"
 	"github.com/huaweicloud/terraform-provider-huaweicloud/huaweicloud/utils/fmtp"
 	"io/ioutil"
+	"log"
 	"strings"
 
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/validation"
 )
 
+// The filter types of LTS dashboards.
+const (
+	dashBoardFilterInput    = "input"
+	dashBoardFilterDropdown = "dropdown"
+	dashBoardFilterTime     = "time"
+)
+
+var dashBoardFilterTypes = []string{dashBoardFilterInput, dashBoardFilterDropdown, dashBoardFilterTime}
+
+// dashBoardFilter is a dashboard filter, the API keeps each filter as a JSON string.
+type dashBoardFilter struct {
+	Field         string   `json:"field"`
+	Type          string   `json:"type"`
+	DefaultValues []string `json:"default_values,omitempty"`
+	SourceQuery   string   `json:"source_query,omitempty"`
+	AppliedCharts []string `json:"applied_charts,omitempty"`
+}
+
+// dashBoardDetail adds the log target, which entity.DashBoard lacks, to the dashboard details. The fields are empty
+// when the API does not return them.
+type dashBoardDetail struct {
+	entity.DashBoard
+	LogGroupId    string `json:"log_group_id"`
+	LogGroupName  string `json:"log_group_name"`
+	LogStreamId   string `json:"log_stream_id"`
+	LogStreamName string `json:"log_stream_name"`
+}
+
+type readDashBoardResp struct {
+	Results []dashBoardDetail `json:"results"`
+}
+
+// dashBoardRequest adds the title and the filters, which entity.DashBoardRequest lacks, to the dashboard payload.
+type dashBoardRequest struct {
+	entity.DashBoardRequest
+	Title   string   `json:"title,omitempty"`
+	Filters []string `json:"filters,omitempty"`
+}
+
 func ResourceLtsDashboard() *schema.Resource {
 	return &schema.Resource{
 		CreateContext: resourceLtsDashBoardCreate,
@@ -25,6 +67,7 @@ func ResourceLtsDashboard() *schema.Resource {
 		Importer: &schema.ResourceImporter{
 			StateContext: schema.ImportStatePassthroughContext,
 		},
+		CustomizeDiff: resourceLtsDashBoardCustomizeDiff,
 
 		Schema: map[string]*schema.Schema{
 			"region": {
@@ -40,10 +83,19 @@ func ResourceLtsDashboard() *schema.Resource {
 			"title": {
 				Type:     schema.TypeString,
 				Optional: true,
+				Computed: true,
 			},
 			"group_name": {
-				Type:     schema.TypeString,
-				Optional: true,
+				Type:          schema.TypeString,
+				Optional:      true,
+				Computed:      true,
+				ConflictsWith: []string{"group_id"},
+			},
+			"group_id": {
+				Type:          schema.TypeString,
+				Optional:      true,
+				Computed:      true,
+				ConflictsWith: []string{"group_name"},
 			},
 			"log_group_id": {
 				Type:     schema.TypeString,
@@ -51,7 +103,7 @@ func ResourceLtsDashboard() *schema.Resource {
 			},
 			"log_group_name": {
 				Type:     schema.TypeString,
-				Required: true,
+				Optional: true,
 			},
 			"log_stream_id": {
 				Type:     schema.TypeString,
@@ -63,17 +115,66 @@ func ResourceLtsDashboard() *schema.Resource {
 			},
 			"log_stream_name": {
 				Type:     schema.TypeString,
-				Required: true,
+				Optional: true,
 			},
 			"template_title": {
-				Type:     schema.TypeList,
-				Required: true,
-				Elem:     &schema.Schema{Type: schema.TypeString},
+				Type:         schema.TypeList,
+				Optional:     true,
+				Elem:         &schema.Schema{Type: schema.TypeString},
+				ExactlyOneOf: []string{"template_title", "dashboard_json"},
+			},
+			"dashboard_json": {
+				Type:             schema.TypeString,
+				Optional:         true,
+				ValidateFunc:     validateDashboardJson,
+				DiffSuppressFunc: suppressDashboardJsonDiffs,
+			},
+			"variables": {
+				Type:         schema.TypeMap,
+				Optional:     true,
+				Elem:         &schema.Schema{Type: schema.TypeString},
+				RequiredWith: []string{"dashboard_json"},
 			},
 			"filters": {
-				Type:     schema.TypeList,
-				Optional: true,
-				Elem:     &schema.Schema{Type: schema.TypeString},
+				Type:          schema.TypeList,
+				Optional:      true,
+				Computed:      true,
+				Elem:          &schema.Schema{Type: schema.TypeString},
+				ConflictsWith: []string{"filter"},
+				Deprecated:    "use filter instead",
+			},
+			"filter": {
+				Type:          schema.TypeList,
+				Optional:      true,
+				Computed:      true,
+				ConflictsWith: []string{"filters", "dashboard_json"},
+				Elem: &schema.Resource{
+					Schema: map[string]*schema.Schema{
+						"field": {
+							Type:     schema.TypeString,
+							Required: true,
+						},
+						"type": {
+							Type:         schema.TypeString,
+							Required:     true,
+							ValidateFunc: validation.StringInSlice(dashBoardFilterTypes, false),
+						},
+						"default_values": {
+							Type:     schema.TypeList,
+							Optional: true,
+							Elem:     &schema.Schema{Type: schema.TypeString},
+						},
+						"source_query": {
+							Type:     schema.TypeString,
+							Optional: true,
+						},
+						"applied_charts": {
+							Type:     schema.TypeList,
+							Optional: true,
+							Elem:     &schema.Schema{Type: schema.TypeString},
+						},
+					},
+				},
 			},
 			"template_type": {
 				Type:     schema.TypeList,
@@ -82,13 +183,155 @@ func ResourceLtsDashboard() *schema.Resource {
 			},
 			"last_update_time": {
 				Type:     schema.TypeInt,
-				Optional: true,
-			},	
+				Computed: true,
+			},
+			"charts": {
+				Type:     schema.TypeList,
+				Computed: true,
+				Elem:     &schema.Schema{Type: schema.TypeString},
+			},
+			"use_system_template": {
+				Type:     schema.TypeBool,
+				Computed: true,
+			},
+		},
+	}
+}
+
+func buildDashBoardFilters(rawFilters []interface{}) ([]string, error) {
+	filters := make([]dashBoardFilter, 0, len(rawFilters))
+	for _, rawFilter := range rawFilters {
+		raw := rawFilter.(map[string]interface{})
+		filters = append(filters, dashBoardFilter{
+			Field:         raw["field"].(string),
+			Type:          raw["type"].(string),
+			DefaultValues: utils.ExpandToStringList(raw["default_values"].([]interface{})),
+			SourceQuery:   raw["source_query"].(string),
+			AppliedCharts: utils.ExpandToStringList(raw["applied_charts"].([]interface{})),
+		})
+	}
+	return encodeDashBoardFilters(filters)
+}
+
+// encodeDashBoardFilters encodes each filter as the JSON string which the API keeps.
+func encodeDashBoardFilters(filters []dashBoardFilter) ([]string, error) {
+	rst := make([]string, 0, len(filters))
+	for _, filter := range filters {
+		b, err := json.Marshal(filter)
+		if err != nil {
+			return nil, err
+		}
+		rst = append(rst, string(b))
+	}
+	return rst, nil
+}
+
+// decodeDashBoardFilters decodes the filters of a dashboard, the filters which are not valid JSON are returned
+// separately.
+func decodeDashBoardFilters(filters []string) ([]dashBoardFilter, []string) {
+	rst := make([]dashBoardFilter, 0, len(filters))
+	var invalid []string
+	for _, filter := range filters {
+		f := dashBoardFilter{}
+		if err := unmarshalEmbeddedJSON([]byte(filter), &f); err != nil {
+			invalid = append(invalid, filter)
+			continue
+		}
+		rst = append(rst, f)
+	}
+	return rst, invalid
+}
+
+// checkDashBoardFilter checks that only dropdown filters take their options from a query and that a time filter has
+// a single default value.
+func checkDashBoardFilter(filter dashBoardFilter) error {
+	if filter.SourceQuery != "" && filter.Type != dashBoardFilterDropdown {
+		return fmt.Errorf("source_query is only supported for dropdown filters")
+	}
+	if filter.Type == dashBoardFilterTime && len(filter.DefaultValues) > 1 {
+		return fmt.Errorf("a time filter takes at most one default value")
+	}
+	return nil
+}
+
+func buildDashBoardRequest(d *schema.ResourceData) (dashBoardRequest, error) {
+	request := dashBoardRequest{
+		DashBoardRequest: entity.DashBoardRequest{
+			LogGroupId:    d.Get("log_group_id").(string),
+			LogGroupName:  d.Get("log_group_name").(string),
+			LogStreamId:   d.Get("log_stream_id").(string),
+			LogStreamName: d.Get("log_stream_name").(string),
+			TemplateTitle: utils.ExpandToStringList(d.Get("template_title").([]interface{})),
+			TemplateType:  utils.ExpandToStringList(d.Get("template_type").([]interface{})),
+			GroupName:     d.Get("group_name").(string),
 		},
+		Title: d.Get("title").(string),
 	}
+	// filter is computed, so only its configured value replaces the filters
+	if !d.GetRawConfig().GetAttr("filter").IsNull() {
+		filters, err := buildDashBoardFilters(d.Get("filter").([]interface{}))
+		if err != nil {
+			return request, err
+		}
+		request.Filters = filters
+	} else {
+		request.Filters = utils.ExpandToStringList(d.Get("filters").([]interface{}))
+	}
+	return request, nil
+}
+
+// flattenDashBoardFilters flattens the filters of a dashboard into filter blocks. The filters which are not valid
+// JSON can not be flattened, they are only kept in filters.
+func flattenDashBoardFilters(filters []string) []map[string]interface{} {
+	decoded, invalid := decodeDashBoardFilters(filters)
+	for _, filter := range invalid {
+		log.Printf("[WARN] the LtsDashBoard filter %s is not valid JSON, it is only kept in filters", filter)
+	}
+	rst := make([]map[string]interface{}, len(decoded))
+	for i, f := range decoded {
+		rst[i] = map[string]interface{}{
+			"field":          f.Field,
+			"type":           f.Type,
+			"default_values": f.DefaultValues,
+			"source_query":   f.SourceQuery,
+			"applied_charts": f.AppliedCharts,
+		}
+	}
+	return rst
+}
+
+// resourceLtsDashBoardCustomizeDiff checks that template dashboards name their log target, and checks the filters:
+// only dropdown filters take their options from a query and a time filter has a single default value.
+func resourceLtsDashBoardCustomizeDiff(_ context.Context, d *schema.ResourceDiff, _ interface{}) error {
+	rawConfig := d.GetRawConfig()
+	if rawConfig.GetAttr("dashboard_json").IsNull() {
+		for _, key := range []string{"log_group_name", "log_stream_name"} {
+			if rawConfig.GetAttr(key).IsNull() {
+				return fmt.Errorf("%s is required for dashboards created from template_title", key)
+			}
+		}
+	}
+	for i, rawFilter := range d.Get("filter").([]interface{}) {
+		raw, ok := rawFilter.(map[string]interface{})
+		if !ok {
+			continue
+		}
+		err := checkDashBoardFilter(dashBoardFilter{
+			Type:          raw["type"].(string),
+			DefaultValues: utils.ExpandToStringList(raw["default_values"].([]interface{})),
+			SourceQuery:   raw["source_query"].(string),
+		})
+		if err != nil {
+			return fmt.Errorf("filter.%d: %s", i, err)
+		}
+	}
+	return nil
 }
 
 func resourceLtsDashBoardCreate(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	if _, ok := d.GetOk("dashboard_json"); ok {
+		return resourceLtsDashBoardCreateFromJson(ctx, d, meta)
+	}
 	config := meta.(*config.Config)
 	client, diaErr := httpclient_go.NewHttpClientGo(config)
 	if diaErr != nil {
@@ -98,15 +341,15 @@ func resourceLtsDashBoardCreate(ctx context.Context, d *schema.ResourceData, met
 	       config.HwClient.ProjectID + "/lts/template-dashboard"
 	header := make(map[string]string)
 	header["content-type"] = "application/json;charset=UTF8"
-	dashBoardRequest := entity.DashBoardRequest{
-		LogGroupId:    d.Get("log_group_id").(string),
-		LogGroupName:  d.Get("log_group_name").(string),
-		LogStreamId:   d.Get("log_stream_id").(string),
-		LogStreamName: d.Get("log_stream_name").(string),
-		TemplateTitle: utils.ExpandToStringList(d.Get("template_title").([]interface{})),
-		TemplateType:  utils.ExpandToStringList(d.Get("template_type").([]interface{})),
-		GroupName:     d.Get("group_name").(string),
+	dashBoardRequest, err := buildDashBoardRequest(d)
+	if err != nil {
+		return diag.Errorf("error building LtsDashBoard filters: %s", err)
 	}
+	groupName, diags := resolveDashBoardGroupName(config, d, dashBoardRequest.GroupName)
+	if diags != nil {
+		return diags
+	}
+	dashBoardRequest.GroupName = groupName
 	client.WithMethod(httpclient_go.MethodPost).WithUrl(url).WithHeader(header).WithBody(dashBoardRequest)
 	response, err := client.Do()
 	if err != nil {
@@ -148,18 +391,76 @@ func resourceLtsDashBoardRead(_ context.Context, d *schema.ResourceData, meta in
 	if body == nil {
 		return diags
 	}
-	rlt := entity.ReadDashBoardResp{}
-	err = json.Unmarshal(body, &rlt)
-	d.Set("region", config.GetRegion(d))
-	if err != nil || len(rlt.Results) == 0 {
-		return diag.Errorf("error read lts dash board %s", d.Id())
+	rlt := readDashBoardResp{}
+	if err := json.Unmarshal(body, &rlt); err != nil {
+		return diag.Errorf("error read lts dash board %s: %s", d.Id(), err)
+	}
+	if len(rlt.Results) == 0 {
+		log.Printf("[WARN] LtsDashBoard %s not found, removing from state", d.Id())
+		d.SetId("")
+		return nil
+	}
+	dashBoard := rlt.Results[0]
+	region := config.GetRegion(d)
+	// the group ID is only looked up when it is not known yet or the dashboard moved to another group
+	groupId := d.Get("group_id").(string)
+	groupName := d.Get("group_name").(string)
+	if dashBoard.GroupName == "" {
+		groupId = ""
+	} else if groupId == "" || (groupName != "" && groupName != dashBoard.GroupName) {
+		group, diags := getDashBoardGroup(config, region, "", dashBoard.GroupName)
+		if diags != nil {
+			return diags
+		}
+		groupId = ""
+		if group != nil {
+			groupId = group.Id
+		} else {
+			log.Printf("[WARN] the group %s of LtsDashBoard %s is not found, group_id is cleared",
+				dashBoard.GroupName, d.Id())
+		}
 	}
 	mErr := multierror.Append(nil,
-		d.Set("title", rlt.Results[0].Title),
+		d.Set("region", region),
+		d.Set("group_id", groupId),
+		d.Set("title", dashBoard.Title),
+		d.Set("group_name", dashBoard.GroupName),
+		d.Set("filters", dashBoard.Filters),
+		d.Set("filter", flattenDashBoardFilters(dashBoard.Filters)),
+		d.Set("last_update_time", dashBoard.LastUpdateTime),
+		d.Set("charts", dashBoard.Charts),
+		d.Set("use_system_template", dashBoard.UseSystemTemplate),
 	)
 	if err := mErr.ErrorOrNil(); err != nil {
 		return fmtp.DiagErrorf("error setting Lts dashboard fields: %s", err)
 	}
+	if diags := setDashBoardLogTarget(d, dashBoard); diags != nil {
+		return diags
+	}
+	if d.Get("dashboard_json").(string) == "" {
+		return nil
+	}
+	return setDashBoardJson(d, config, region, &dashBoard.DashBoard)
+}
+
+// setDashBoardLogTarget refreshes the log group and log stream of a dashboard when the API returns them, the
+// configured values are kept otherwise. The template titles and types are only used to create the dashboard and
+// cannot be read back.
+func setDashBoardLogTarget(d *schema.ResourceData, dashBoard dashBoardDetail) diag.Diagnostics {
+	mErr := &multierror.Error{}
+	for key, value := range map[string]string{
+		"log_group_id":    dashBoard.LogGroupId,
+		"log_group_name":  dashBoard.LogGroupName,
+		"log_stream_id":   dashBoard.LogStreamId,
+		"log_stream_name": dashBoard.LogStreamName,
+	} {
+		if value != "" {
+			mErr = multierror.Append(mErr, d.Set(key, value))
+		}
+	}
+	if err := mErr.ErrorOrNil(); err != nil {
+		return fmtp.DiagErrorf("error setting Lts dashboard fields: %s", err)
+	}
 	return nil
 }
 
@@ -190,7 +491,10 @@ func resourceLtsDashBoardDelete(_ context.Context, d *schema.ResourceData, meta
 }
 
 
-func resourceDashBoardUpdate(_ context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+func resourceDashBoardUpdate(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	if _, ok := d.GetOk("dashboard_json"); ok {
+		return resourceDashBoardUpdateFromJson(ctx, d, meta)
+	}
 	config := meta.(*config.Config)
 	client, diaErr := httpclient_go.NewHttpClientGo(config)
 	if diaErr != nil {
@@ -200,15 +504,15 @@ func resourceDashBoardUpdate(_ context.Context, d *schema.ResourceData, meta int
 	    config.HwClient.ProjectID + "/dashboard?id=" + d.Id()
 	header := make(map[string]string)
 	header["content-type"] = "application/json;charset=UTF8"
-	dashBoardRequest := entity.DashBoardRequest{
-		LogGroupId:    d.Get("log_group_id").(string),
-		LogGroupName:  d.Get("log_group_name").(string),
-		LogStreamId:   d.Get("log_stream_id").(string),
-		LogStreamName: d.Get("log_stream_name").(string),
-		TemplateTitle: utils.ExpandToStringList(d.Get("template_title").([]interface{})),
-		TemplateType:  utils.ExpandToStringList(d.Get("template_type").([]interface{})),
-		GroupName:     d.Get("group_name").(string),
+	dashBoardRequest, err := buildDashBoardRequest(d)
+	if err != nil {
+		return diag.Errorf("error building LtsDashBoard filters: %s", err)
 	}
+	groupName, diags := resolveDashBoardGroupName(config, d, dashBoardRequest.GroupName)
+	if diags != nil {
+		return diags
+	}
+	dashBoardRequest.GroupName = groupName
 	client.WithMethod(httpclient_go.MethodPut).WithUrl(url).WithHeader(header).WithBody(dashBoardRequest)
 	response, err := client.Do()
 	if err != nil {
@@ -219,14 +523,153 @@ func resourceDashBoardUpdate(_ context.Context, d *schema.ResourceData, meta int
 	if err != nil {
 		return diag.Errorf("error convert data %s: %s", string(body), err)
 	}
+	// the dashboard keeps its ID, the response is not needed
 	if response.StatusCode == 200 {
-		rlt := make([]entity.DashBoard, 0)
-		err = json.Unmarshal(body, &rlt)
+		return resourceLtsDashBoardRead(ctx, d, meta)
+	}
+	return diag.Errorf("error update LtsDashBoard fields %s: %s", dashBoardRequest, string(body)) 
+}
+
+// setDashBoardJson refreshes the dashboard_json of a dashboard managed as a document, the log group and log stream
+// of the dashboard are replaced by their variables.
+func setDashBoardJson(d *schema.ResourceData, cfg *config.Config, region string,
+	dashBoard *entity.DashBoard) diag.Diagnostics {
+	document, diags := renderDashboardDocument(cfg, region, dashBoard)
+	if diags != nil {
+		return diags
+	}
+	templateDashboardDocument(document, buildDashboardVariables(d))
+	b, err := json.Marshal(document)
+	if err != nil {
+		return diag.Errorf("error rendering LtsDashBoard %s: %s", d.Id(), err)
+	}
+	dashBoardJson, err := normalizeDashboardJson(string(b))
+	if err != nil {
+		return diag.Errorf("error rendering LtsDashBoard %s: %s", d.Id(), err)
+	}
+	if err := d.Set("dashboard_json", dashBoardJson); err != nil {
+		return diag.Errorf("error setting Lts dashboard fields: %s", err)
+	}
+	return nil
+}
+
+// buildDashboardDocument decodes the dashboard_json of a dashboard and binds its variables to the log group and
+// log stream of the dashboard.
+func buildDashboardDocument(d *schema.ResourceData) (*dashboardDocument, error) {
+	document, err := decodeDashboardDocument(d.Get("dashboard_json").(string))
+	if err != nil {
+		return nil, err
+	}
+	if document.Title == "" {
+		document.Title = d.Get("title").(string)
+	}
+	if document.GroupName == "" {
+		document.GroupName = d.Get("group_name").(string)
+	}
+	err = expandDashboardDocument(document, buildDashboardVariables(d))
+	return document, err
+}
+
+// buildDashboardVariables returns the IDs bound to the variables of a dashboard document, log_group_id and
+// log_stream_id are bound to the log target of the dashboard.
+func buildDashboardVariables(d *schema.ResourceData) map[string]string {
+	variables := make(map[string]string)
+	for name, id := range d.Get("variables").(map[string]interface{}) {
+		variables[name] = id.(string)
+	}
+	variables[logGroupIdVariable] = d.Get("log_group_id").(string)
+	variables[logStreamIdVariable] = d.Get("log_stream_id").(string)
+	return variables
+}
+
+func createDashboardCharts(cfg *config.Config, region, dashBoardId string, charts []dashboardChart) diag.Diagnostics {
+	for _, chart := range charts {
+		chart.Id = ""
+		chart.DashboardId = dashBoardId
+		rlt := dashboardChart{}
+		statusCode, diags := doLtsRequest(cfg, region, httpclient_go.MethodPost, "/dashboard/charts", chart, &rlt)
+		if diags != nil {
+			return diags
+		}
+		if statusCode == 404 || rlt.Id == "" {
+			return diag.Errorf("error creating chart %s of LtsDashBoard %s: no chart ID returned", chart.Title,
+				dashBoardId)
+		}
+	}
+	return nil
+}
+
+func resourceLtsDashBoardCreateFromJson(ctx context.Context, d *schema.ResourceData,
+	meta interface{}) diag.Diagnostics {
+	cfg := meta.(*config.Config)
+	region := cfg.GetRegion(d)
+	document, err := buildDashboardDocument(d)
+	if err != nil {
+		return diag.Errorf("error creating LtsDashBoard from dashboard_json: %s", err)
+	}
+	groupName, diags := resolveDashBoardGroupName(cfg, d, document.GroupName)
+	if diags != nil {
+		return diags
+	}
+	document.GroupName = groupName
+	rlt := entity.DashBoard{}
+	request, err := buildDashboardInfoRequest(document.dashboardInfo)
+	if err != nil {
+		return diag.Errorf("error building LtsDashBoard filters: %s", err)
+	}
+	statusCode, diags := doLtsRequest(cfg, region, httpclient_go.MethodPost, "/dashboard", request, &rlt)
+	if diags != nil {
+		return diags
+	}
+	if statusCode == 404 || rlt.Id == "" {
+		return diag.Errorf("error creating LtsDashBoard %s: no dashboard ID returned", document.Title)
+	}
+	d.SetId(rlt.Id)
+	if diags := createDashboardCharts(cfg, region, rlt.Id, document.Charts); diags != nil {
+		return diags
+	}
+	return resourceLtsDashBoardRead(ctx, d, meta)
+}
+
+// resourceDashBoardUpdateFromJson updates a dashboard managed as a document, its charts are replaced when the
+// document or the log target changes.
+func resourceDashBoardUpdateFromJson(ctx context.Context, d *schema.ResourceData,
+	meta interface{}) diag.Diagnostics {
+	cfg := meta.(*config.Config)
+	region := cfg.GetRegion(d)
+	document, err := buildDashboardDocument(d)
+	if err != nil {
+		return diag.Errorf("error update LtsDashBoard %s from dashboard_json: %s", d.Id(), err)
+	}
+	groupName, diags := resolveDashBoardGroupName(cfg, d, document.GroupName)
+	if diags != nil {
+		return diags
+	}
+	document.GroupName = groupName
+	if d.HasChanges("dashboard_json", "title", "group_name", "group_id") {
+		request, err := buildDashboardInfoRequest(document.dashboardInfo)
 		if err != nil {
-			return diag.Errorf("error convert data %s: %s", string(body), err)
+			return diag.Errorf("error building LtsDashBoard filters: %s", err)
+		}
+		statusCode, diags := doLtsRequest(cfg, region, httpclient_go.MethodPut, "/dashboard?id="+d.Id(), request,
+			nil)
+		if diags != nil {
+			return diags
+		}
+		if statusCode == 404 {
+			return diag.Errorf("error update LtsDashBoard %s: the dashboard does not exist", d.Id())
 		}
-		d.SetId(rlt[0].Id)
-		return nil
 	}
-	return diag.Errorf("error update LtsDashBoard fields %s: %s", dashBoardRequest, string(body)) 
+	if d.HasChanges("dashboard_json", "variables", "log_group_id", "log_stream_id") {
+		for _, chartId := range utils.ExpandToStringList(d.Get("charts").([]interface{})) {
+			if _, diags := doLtsRequest(cfg, region, httpclient_go.MethodDelete,
+				"/dashboard/charts?dashboard_id="+d.Id()+"&id="+chartId, nil, nil); diags != nil {
+				return diags
+			}
+		}
+		if diags := createDashboardCharts(cfg, region, d.Id(), document.Charts); diags != nil {
+			return diags
+		}
+	}
+	return resourceLtsDashBoardRead(ctx, d, meta)
 }
@@ -0,0 +1,454 @@
+package lts
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"strings"
+
+	"github.com/hashicorp/go-multierror"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/validation"
+	"github.com/huaweicloud/terraform-provider-huaweicloud/huaweicloud/config"
+	"github.com/huaweicloud/terraform-provider-huaweicloud/huaweicloud/services/internal/httpclient_go"
+	"github.com/huaweicloud/terraform-provider-huaweicloud/huaweicloud/utils"
+)
+
+// The chart types supported by LTS dashboards.
+const (
+	chartTypeTable  = "table"
+	chartTypeLine   = "line"
+	chartTypeBar    = "bar"
+	chartTypePie    = "pie"
+	chartTypeNumber = "number"
+	chartTypeMap    = "map"
+)
+
+var dashboardChartTypes = []string{
+	chartTypeTable, chartTypeLine, chartTypeBar, chartTypePie, chartTypeNumber, chartTypeMap,
+}
+
+type dashboardChartTimeRange struct {
+	RelativeMinutes int   `json:"relative_minutes,omitempty"`
+	StartTime       int64 `json:"start_time,omitempty"`
+	EndTime         int64 `json:"end_time,omitempty"`
+}
+
+type dashboardChartAxis struct {
+	XField  string   `json:"x_field"`
+	YFields []string `json:"y_fields"`
+	XTitle  string   `json:"x_title,omitempty"`
+	YTitle  string   `json:"y_title,omitempty"`
+}
+
+type dashboardChartLegend struct {
+	Show     bool   `json:"show"`
+	Position string `json:"position,omitempty"`
+}
+
+type dashboardChartLayout struct {
+	X      int `json:"x"`
+	Y      int `json:"y"`
+	Width  int `json:"width"`
+	Height int `json:"height"`
+}
+
+type dashboardChartConfig struct {
+	TimeRange *dashboardChartTimeRange `json:"time_range,omitempty"`
+	Axis      *dashboardChartAxis      `json:"axis,omitempty"`
+	Legend    *dashboardChartLegend    `json:"legend,omitempty"`
+	Layout    *dashboardChartLayout    `json:"layout,omitempty"`
+}
+
+type dashboardChart struct {
+	Id          string               `json:"id,omitempty"`
+	DashboardId string               `json:"dashboard_id,omitempty"`
+	Title       string               `json:"title"`
+	Type        string               `json:"type"`
+	LogGroupId  string               `json:"log_group_id,omitempty"`
+	LogStreamId string               `json:"log_stream_id,omitempty"`
+	Sql         string               `json:"sql"`
+	Config      dashboardChartConfig `json:"config"`
+}
+
+type dashboardChartList struct {
+	Results []dashboardChart `json:"results"`
+}
+
+func ResourceLtsDashboardChart() *schema.Resource {
+	return &schema.Resource{
+		CreateContext: resourceLtsDashboardChartCreate,
+		ReadContext:   resourceLtsDashboardChartRead,
+		UpdateContext: resourceLtsDashboardChartUpdate,
+		DeleteContext: resourceLtsDashboardChartDelete,
+		Importer: &schema.ResourceImporter{
+			StateContext: resourceLtsDashboardChartImportState,
+		},
+		CustomizeDiff: resourceLtsDashboardChartCustomizeDiff,
+
+		Schema: map[string]*schema.Schema{
+			"region": {
+				Type:     schema.TypeString,
+				Optional: true,
+				Computed: true,
+				ForceNew: true,
+			},
+			"dashboard_id": {
+				Type:     schema.TypeString,
+				Required: true,
+				ForceNew: true,
+			},
+			"title": {
+				Type:     schema.TypeString,
+				Required: true,
+			},
+			"type": {
+				Type:         schema.TypeString,
+				Required:     true,
+				ValidateFunc: validation.StringInSlice(dashboardChartTypes, false),
+			},
+			"log_group_id": {
+				Type:     schema.TypeString,
+				Required: true,
+			},
+			"log_stream_id": {
+				Type:     schema.TypeString,
+				Required: true,
+			},
+			"sql": {
+				Type:         schema.TypeString,
+				Required:     true,
+				ValidateFunc: validation.StringIsNotWhiteSpace,
+			},
+			"time_range": {
+				Type:     schema.TypeList,
+				Optional: true,
+				Computed: true,
+				MaxItems: 1,
+				Elem: &schema.Resource{
+					Schema: map[string]*schema.Schema{
+						"relative_minutes": {
+							Type:          schema.TypeInt,
+							Optional:      true,
+							ValidateFunc:  validation.IntAtLeast(1),
+							ConflictsWith: []string{"time_range.0.start_time", "time_range.0.end_time"},
+						},
+						"start_time": {
+							Type:         schema.TypeInt,
+							Optional:     true,
+							RequiredWith: []string{"time_range.0.end_time"},
+						},
+						"end_time": {
+							Type:         schema.TypeInt,
+							Optional:     true,
+							RequiredWith: []string{"time_range.0.start_time"},
+						},
+					},
+				},
+			},
+			"axis": {
+				Type:     schema.TypeList,
+				Optional: true,
+				MaxItems: 1,
+				Elem: &schema.Resource{
+					Schema: map[string]*schema.Schema{
+						"x_field": {
+							Type:     schema.TypeString,
+							Required: true,
+						},
+						"y_fields": {
+							Type:     schema.TypeList,
+							Required: true,
+							MinItems: 1,
+							Elem:     &schema.Schema{Type: schema.TypeString},
+						},
+						"x_title": {
+							Type:     schema.TypeString,
+							Optional: true,
+						},
+						"y_title": {
+							Type:     schema.TypeString,
+							Optional: true,
+						},
+					},
+				},
+			},
+			"legend": {
+				Type:     schema.TypeList,
+				Optional: true,
+				Computed: true,
+				MaxItems: 1,
+				Elem: &schema.Resource{
+					Schema: map[string]*schema.Schema{
+						"show": {
+							Type:     schema.TypeBool,
+							Optional: true,
+							Default:  true,
+						},
+						"position": {
+							Type:         schema.TypeString,
+							Optional:     true,
+							Computed:     true,
+							ValidateFunc: validation.StringInSlice([]string{"top", "bottom", "left", "right"}, false),
+						},
+					},
+				},
+			},
+			"layout": {
+				Type:     schema.TypeList,
+				Optional: true,
+				Computed: true,
+				MaxItems: 1,
+				Elem: &schema.Resource{
+					Schema: map[string]*schema.Schema{
+						"x": {
+							Type:         schema.TypeInt,
+							Required:     true,
+							ValidateFunc: validation.IntAtLeast(0),
+						},
+						"y": {
+							Type:         schema.TypeInt,
+							Required:     true,
+							ValidateFunc: validation.IntAtLeast(0),
+						},
+						"width": {
+							Type:         schema.TypeInt,
+							Required:     true,
+							ValidateFunc: validation.IntAtLeast(1),
+						},
+						"height": {
+							Type:         schema.TypeInt,
+							Required:     true,
+							ValidateFunc: validation.IntAtLeast(1),
+						},
+					},
+				},
+			},
+		},
+	}
+}
+
+// resourceLtsDashboardChartCustomizeDiff checks that the axis and legend settings fit the chart type: line and bar
+// charts plot their values along axes, tables and single numbers have neither axes nor a legend.
+func resourceLtsDashboardChartCustomizeDiff(_ context.Context, d *schema.ResourceDiff, _ interface{}) error {
+	chartType := d.Get("type").(string)
+	_, hasAxis := d.GetOk("axis")
+	switch chartType {
+	case chartTypeLine, chartTypeBar:
+		if !hasAxis && d.NewValueKnown("axis") {
+			return fmt.Errorf("axis is required for %s charts", chartType)
+		}
+	case chartTypeTable, chartTypeNumber:
+		if hasAxis {
+			return fmt.Errorf("axis is not supported for %s charts", chartType)
+		}
+		legend := d.GetRawConfig().GetAttr("legend")
+		if legend.IsKnown() && !legend.IsNull() && legend.LengthInt() > 0 {
+			return fmt.Errorf("legend is not supported for %s charts", chartType)
+		}
+	}
+	return nil
+}
+
+func buildDashboardChartConfig(d *schema.ResourceData) dashboardChartConfig {
+	chartConfig := dashboardChartConfig{}
+	if v, ok := d.GetOk("time_range"); ok && v.([]interface{})[0] != nil {
+		raw := v.([]interface{})[0].(map[string]interface{})
+		chartConfig.TimeRange = &dashboardChartTimeRange{
+			RelativeMinutes: raw["relative_minutes"].(int),
+			StartTime:       int64(raw["start_time"].(int)),
+			EndTime:         int64(raw["end_time"].(int)),
+		}
+	}
+	if v, ok := d.GetOk("axis"); ok && v.([]interface{})[0] != nil {
+		raw := v.([]interface{})[0].(map[string]interface{})
+		chartConfig.Axis = &dashboardChartAxis{
+			XField:  raw["x_field"].(string),
+			YFields: utils.ExpandToStringList(raw["y_fields"].([]interface{})),
+			XTitle:  raw["x_title"].(string),
+			YTitle:  raw["y_title"].(string),
+		}
+	}
+	if v, ok := d.GetOk("legend"); ok && v.([]interface{})[0] != nil {
+		raw := v.([]interface{})[0].(map[string]interface{})
+		chartConfig.Legend = &dashboardChartLegend{
+			Show:     raw["show"].(bool),
+			Position: raw["position"].(string),
+		}
+	}
+	if v, ok := d.GetOk("layout"); ok && v.([]interface{})[0] != nil {
+		raw := v.([]interface{})[0].(map[string]interface{})
+		chartConfig.Layout = &dashboardChartLayout{
+			X:      raw["x"].(int),
+			Y:      raw["y"].(int),
+			Width:  raw["width"].(int),
+			Height: raw["height"].(int),
+		}
+	}
+	return chartConfig
+}
+
+func buildDashboardChart(d *schema.ResourceData) dashboardChart {
+	return dashboardChart{
+		DashboardId: d.Get("dashboard_id").(string),
+		Title:       d.Get("title").(string),
+		Type:        d.Get("type").(string),
+		LogGroupId:  d.Get("log_group_id").(string),
+		LogStreamId: d.Get("log_stream_id").(string),
+		Sql:         d.Get("sql").(string),
+		Config:      buildDashboardChartConfig(d),
+	}
+}
+
+func flattenDashboardChartTimeRange(timeRange *dashboardChartTimeRange) []map[string]interface{} {
+	if timeRange == nil {
+		return nil
+	}
+	return []map[string]interface{}{
+		{
+			"relative_minutes": timeRange.RelativeMinutes,
+			"start_time":       timeRange.StartTime,
+			"end_time":         timeRange.EndTime,
+		},
+	}
+}
+
+func flattenDashboardChartAxis(axis *dashboardChartAxis) []map[string]interface{} {
+	if axis == nil {
+		return nil
+	}
+	return []map[string]interface{}{
+		{
+			"x_field":  axis.XField,
+			"y_fields": axis.YFields,
+			"x_title":  axis.XTitle,
+			"y_title":  axis.YTitle,
+		},
+	}
+}
+
+func flattenDashboardChartLegend(legend *dashboardChartLegend) []map[string]interface{} {
+	if legend == nil {
+		return nil
+	}
+	return []map[string]interface{}{
+		{
+			"show":     legend.Show,
+			"position": legend.Position,
+		},
+	}
+}
+
+func flattenDashboardChartLayout(layout *dashboardChartLayout) []map[string]interface{} {
+	if layout == nil {
+		return nil
+	}
+	return []map[string]interface{}{
+		{
+			"x":      layout.X,
+			"y":      layout.Y,
+			"width":  layout.Width,
+			"height": layout.Height,
+		},
+	}
+}
+
+// getDashboardChart queries a chart by its ID, a nil chart is returned if it does not exist.
+func getDashboardChart(cfg *config.Config, region, chartId string) (*dashboardChart, diag.Diagnostics) {
+	rlt := dashboardChartList{}
+	statusCode, diags := doLtsRequest(cfg, region, httpclient_go.MethodGet, "/dashboard/charts?id="+chartId, nil, &rlt)
+	if diags != nil {
+		return nil, diags
+	}
+	if statusCode == 404 || len(rlt.Results) == 0 {
+		return nil, nil
+	}
+	return &rlt.Results[0], nil
+}
+
+func resourceLtsDashboardChartCreate(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	cfg := meta.(*config.Config)
+	chart := buildDashboardChart(d)
+	rlt := dashboardChart{}
+	statusCode, diags := doLtsRequest(cfg, cfg.GetRegion(d), httpclient_go.MethodPost, "/dashboard/charts", chart, &rlt)
+	if diags != nil {
+		return diags
+	}
+	if statusCode == 404 {
+		return diag.Errorf("error creating LtsDashboardChart %s: the dashboard %s does not exist", chart.Title,
+			chart.DashboardId)
+	}
+	if rlt.Id == "" {
+		return diag.Errorf("error creating LtsDashboardChart %s: no chart ID returned", chart.Title)
+	}
+	d.SetId(rlt.Id)
+	return resourceLtsDashboardChartRead(ctx, d, meta)
+}
+
+func resourceLtsDashboardChartRead(_ context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	cfg := meta.(*config.Config)
+	region := cfg.GetRegion(d)
+	chart, diags := getDashboardChart(cfg, region, d.Id())
+	if diags != nil {
+		return diags
+	}
+	if chart == nil {
+		log.Printf("[WARN] LtsDashboardChart %s not found, removing from state", d.Id())
+		d.SetId("")
+		return nil
+	}
+	dashboardId := chart.DashboardId
+	if dashboardId == "" {
+		dashboardId = d.Get("dashboard_id").(string)
+	}
+	mErr := multierror.Append(nil,
+		d.Set("region", region),
+		d.Set("dashboard_id", dashboardId),
+		d.Set("title", chart.Title),
+		d.Set("type", chart.Type),
+		d.Set("log_group_id", chart.LogGroupId),
+		d.Set("log_stream_id", chart.LogStreamId),
+		d.Set("sql", chart.Sql),
+		d.Set("time_range", flattenDashboardChartTimeRange(chart.Config.TimeRange)),
+		d.Set("axis", flattenDashboardChartAxis(chart.Config.Axis)),
+		d.Set("legend", flattenDashboardChartLegend(chart.Config.Legend)),
+		d.Set("layout", flattenDashboardChartLayout(chart.Config.Layout)),
+	)
+	if err := mErr.ErrorOrNil(); err != nil {
+		return diag.Errorf("error setting LtsDashboardChart fields: %s", err)
+	}
+	return nil
+}
+
+func resourceLtsDashboardChartUpdate(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	cfg := meta.(*config.Config)
+	chart := buildDashboardChart(d)
+	chart.Id = d.Id()
+	statusCode, diags := doLtsRequest(cfg, cfg.GetRegion(d), httpclient_go.MethodPut, "/dashboard/charts?id="+d.Id(),
+		chart, nil)
+	if diags != nil {
+		return diags
+	}
+	if statusCode == 404 {
+		return diag.Errorf("error update LtsDashboardChart %s: the chart does not exist", d.Id())
+	}
+	return resourceLtsDashboardChartRead(ctx, d, meta)
+}
+
+func resourceLtsDashboardChartDelete(_ context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	cfg := meta.(*config.Config)
+	_, diags := doLtsRequest(cfg, cfg.GetRegion(d), httpclient_go.MethodDelete,
+		"/dashboard/charts?dashboard_id="+d.Get("dashboard_id").(string)+"&id="+d.Id(), nil, nil)
+	return diags
+}
+
+func resourceLtsDashboardChartImportState(_ context.Context, d *schema.ResourceData,
+	_ interface{}) ([]*schema.ResourceData, error) {
+	parts := strings.Split(d.Id(), "/")
+	if len(parts) != 2 || parts[0] == "" || parts[1] == "" {
+		return nil, fmt.Errorf("invalid format specified for import ID, must be <dashboard_id>/<chart_id>")
+	}
+	d.SetId(parts[1])
+	return []*schema.ResourceData{d}, d.Set("dashboard_id", parts[0])
+}
@@ -0,0 +1,197 @@
+package lts
+
+import (
+	"context"
+	"log"
+	"strings"
+
+	"github.com/hashicorp/go-multierror"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/huaweicloud/terraform-provider-huaweicloud/huaweicloud/config"
+	"github.com/huaweicloud/terraform-provider-huaweicloud/huaweicloud/services/internal/httpclient_go"
+)
+
+type dashBoardGroup struct {
+	Id         string   `json:"id,omitempty"`
+	GroupName  string   `json:"group_name"`
+	Dashboards []string `json:"dashboards,omitempty"`
+}
+
+type dashBoardGroupList struct {
+	Results []dashBoardGroup `json:"results"`
+}
+
+func ResourceLtsDashboardGroup() *schema.Resource {
+	return &schema.Resource{
+		CreateContext: resourceLtsDashBoardGroupCreate,
+		ReadContext:   resourceLtsDashBoardGroupRead,
+		UpdateContext: resourceLtsDashBoardGroupUpdate,
+		DeleteContext: resourceLtsDashBoardGroupDelete,
+		Importer: &schema.ResourceImporter{
+			StateContext: schema.ImportStatePassthroughContext,
+		},
+
+		Schema: map[string]*schema.Schema{
+			"region": {
+				Type:     schema.TypeString,
+				Optional: true,
+				Computed: true,
+				ForceNew: true,
+			},
+			"name": {
+				Type:     schema.TypeString,
+				Required: true,
+			},
+			"delete_dashboards": {
+				Type:     schema.TypeBool,
+				Optional: true,
+				Default:  false,
+				Description: "Whether to delete the dashboards of the group, with their charts, when the group is " +
+					"deleted. This also deletes the dashboards which are managed by other resources.",
+			},
+			"dashboards": {
+				Type:     schema.TypeList,
+				Computed: true,
+				Elem:     &schema.Schema{Type: schema.TypeString},
+			},
+		},
+	}
+}
+
+// listDashBoardGroups queries all dashboard groups of the project.
+func listDashBoardGroups(cfg *config.Config, region string) ([]dashBoardGroup, diag.Diagnostics) {
+	rlt := dashBoardGroupList{}
+	statusCode, diags := doLtsRequest(cfg, region, httpclient_go.MethodGet, "/dashboard-group", nil, &rlt)
+	if diags != nil {
+		return nil, diags
+	}
+	if statusCode == 404 {
+		return nil, nil
+	}
+	return rlt.Results, nil
+}
+
+// getDashBoardGroup returns the dashboard group with the given ID, or with the given name when the ID is empty.
+// A nil group is returned if there is no such group.
+func getDashBoardGroup(cfg *config.Config, region, id, name string) (*dashBoardGroup, diag.Diagnostics) {
+	groups, diags := listDashBoardGroups(cfg, region)
+	if diags != nil {
+		return nil, diags
+	}
+	for i, group := range groups {
+		if (id != "" && group.Id == id) || (id == "" && group.GroupName == name) {
+			return &groups[i], nil
+		}
+	}
+	return nil, nil
+}
+
+// resolveDashBoardGroupName returns the name of the group referenced by the group_id of a dashboard, or groupName
+// when group_id is not configured. The group_id in the state is not used, it is computed from the group name.
+func resolveDashBoardGroupName(cfg *config.Config, d *schema.ResourceData,
+	groupName string) (string, diag.Diagnostics) {
+	if d.GetRawConfig().GetAttr("group_id").IsNull() {
+		return groupName, nil
+	}
+	groupId := d.Get("group_id").(string)
+	group, diags := getDashBoardGroup(cfg, cfg.GetRegion(d), groupId, "")
+	if diags != nil {
+		return "", diags
+	}
+	if group == nil {
+		return "", diag.Errorf("the dashboard group %s does not exist", groupId)
+	}
+	return group.GroupName, nil
+}
+
+func resourceLtsDashBoardGroupCreate(ctx context.Context, d *schema.ResourceData,
+	meta interface{}) diag.Diagnostics {
+	cfg := meta.(*config.Config)
+	group := dashBoardGroup{
+		GroupName: d.Get("name").(string),
+	}
+	rlt := dashBoardGroup{}
+	statusCode, diags := doLtsRequest(cfg, cfg.GetRegion(d), httpclient_go.MethodPost, "/dashboard-group", group, &rlt)
+	if diags != nil {
+		return diags
+	}
+	if statusCode == 404 || rlt.Id == "" {
+		return diag.Errorf("error creating LtsDashBoardGroup %s: no group ID returned", group.GroupName)
+	}
+	d.SetId(rlt.Id)
+	return resourceLtsDashBoardGroupRead(ctx, d, meta)
+}
+
+func resourceLtsDashBoardGroupRead(_ context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	cfg := meta.(*config.Config)
+	region := cfg.GetRegion(d)
+	group, diags := getDashBoardGroup(cfg, region, d.Id(), "")
+	if diags != nil {
+		return diags
+	}
+	if group == nil {
+		log.Printf("[WARN] LtsDashBoardGroup %s not found, removing from state", d.Id())
+		d.SetId("")
+		return nil
+	}
+	mErr := multierror.Append(nil,
+		d.Set("region", region),
+		d.Set("name", group.GroupName),
+		d.Set("dashboards", group.Dashboards),
+	)
+	if err := mErr.ErrorOrNil(); err != nil {
+		return diag.Errorf("error setting LtsDashBoardGroup fields: %s", err)
+	}
+	return nil
+}
+
+func resourceLtsDashBoardGroupUpdate(ctx context.Context, d *schema.ResourceData,
+	meta interface{}) diag.Diagnostics {
+	cfg := meta.(*config.Config)
+	if d.HasChange("name") {
+		group := dashBoardGroup{
+			Id:        d.Id(),
+			GroupName: d.Get("name").(string),
+		}
+		statusCode, diags := doLtsRequest(cfg, cfg.GetRegion(d), httpclient_go.MethodPut,
+			"/dashboard-group?id="+d.Id(), group, nil)
+		if diags != nil {
+			return diags
+		}
+		if statusCode == 404 {
+			return diag.Errorf("error update LtsDashBoardGroup %s: the group does not exist", d.Id())
+		}
+	}
+	return resourceLtsDashBoardGroupRead(ctx, d, meta)
+}
+
+// resourceLtsDashBoardGroupDelete deletes a dashboard group. The dashboards of the group, with their charts, are
+// deleted first when delete_dashboards is set, otherwise a group which still has dashboards is not deleted.
+func resourceLtsDashBoardGroupDelete(_ context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	cfg := meta.(*config.Config)
+	region := cfg.GetRegion(d)
+	group, diags := getDashBoardGroup(cfg, region, d.Id(), "")
+	if diags != nil {
+		return diags
+	}
+	if group == nil {
+		return nil
+	}
+	if len(group.Dashboards) > 0 {
+		if !d.Get("delete_dashboards").(bool) {
+			return diag.Errorf("error delete LtsDashBoardGroup %s: the group still contains the dashboards %s, "+
+				"set delete_dashboards to delete them with the group", d.Id(), strings.Join(group.Dashboards, ", "))
+		}
+		for _, dashBoardId := range group.Dashboards {
+			log.Printf("[WARN] deleting LtsDashBoard %s with LtsDashBoardGroup %s, it may be managed elsewhere",
+				dashBoardId, d.Id())
+			if _, diags := doLtsRequest(cfg, region, httpclient_go.MethodDelete,
+				"/dashboard?is_delete_charts=true&id="+dashBoardId, nil, nil); diags != nil {
+				return diags
+			}
+		}
+	}
+	_, diags = doLtsRequest(cfg, region, httpclient_go.MethodDelete, "/dashboard-group?id="+d.Id(), nil, nil)
+	return diags
+}
@@ -0,0 +1,346 @@
+package lts
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+// The parse types of custom struct templates.
+const (
+	parseTypeSplit = "split"
+	parseTypeJson  = "json"
+	parseTypeRegex = "regex"
+	parseTypeNginx = "nginx"
+)
+
+// The field types supported by struct templates.
+const (
+	fieldTypeString = "string"
+	fieldTypeLong   = "long"
+	fieldTypeFloat  = "float"
+)
+
+var (
+	javaNamedGroupRegexp = regexp.MustCompile(`\(\?<([A-Za-z_][A-Za-z0-9_]*)>`)
+	javaOnlyRegexp       = regexp.MustCompile(`(\\[1-9]|\\k<|\(\?(?:[=!>]|<[=!])|[*+?}]\+)|\\.`)
+	nginxQuotedRegexp    = regexp.MustCompile(`'((?:[^'\\]|\\.)*)'|"((?:[^"\\]|\\.)*)"`)
+	nginxVariableRegexp  = regexp.MustCompile(`\$(?:\{([A-Za-z0-9_]+)\}|([A-Za-z0-9_]+))`)
+	nginxDirectiveRegexp = regexp.MustCompile(`^\s*log_format\s+\S+\s+`)
+	nginxUnescaper       = strings.NewReplacer(`\\`, `\`, `\"`, `"`, `\'`, `'`)
+)
+
+// nginxVariableTypes lists the nginx variables with numeric values, all other variables are strings.
+var nginxVariableTypes = map[string]string{
+	"status":                 fieldTypeLong,
+	"body_bytes_sent":        fieldTypeLong,
+	"bytes_sent":             fieldTypeLong,
+	"request_length":         fieldTypeLong,
+	"connection":             fieldTypeLong,
+	"connection_requests":    fieldTypeLong,
+	"content_length":         fieldTypeLong,
+	"remote_port":            fieldTypeLong,
+	"server_port":            fieldTypeLong,
+	"pid":                    fieldTypeLong,
+	"request_time":           fieldTypeFloat,
+	"upstream_response_time": fieldTypeFloat,
+	"upstream_connect_time":  fieldTypeFloat,
+	"upstream_header_time":   fieldTypeFloat,
+	"msec":                   fieldTypeFloat,
+}
+
+// parsedStructField is a field extracted from a sample log, it mirrors the demo fields of a struct template.
+type parsedStructField struct {
+	FieldName string
+	Type      string
+	Content   string
+	Index     int
+}
+
+// structParseRules holds the arguments which control how a custom struct template splits a log line.
+type structParseRules struct {
+	ParseType  string
+	Tokenizer  string
+	RegexRules string
+	Layers     int
+	LogFormat  string
+}
+
+// parseStructSample extracts the fields of a sample log the same way LTS does for the given parse rules.
+func parseStructSample(rules structParseRules, content string) ([]parsedStructField, error) {
+	if content == "" {
+		return nil, fmt.Errorf("the sample log is empty")
+	}
+	switch rules.ParseType {
+	case parseTypeSplit:
+		return parseSplitSample(rules.Tokenizer, content)
+	case parseTypeJson:
+		return parseJsonSample(rules.Layers, content)
+	case parseTypeRegex:
+		return parseRegexSample(rules.RegexRules, content)
+	case parseTypeNginx:
+		return parseNginxSample(rules.LogFormat, content)
+	default:
+		return nil, fmt.Errorf("unsupported parse type %q", rules.ParseType)
+	}
+}
+
+func parseSplitSample(tokenizer, content string) ([]parsedStructField, error) {
+	if tokenizer == "" {
+		return nil, fmt.Errorf("a tokenizer is required for the split parse type")
+	}
+	values := strings.Split(content, tokenizer)
+	fields := make([]parsedStructField, len(values))
+	for i, value := range values {
+		fields[i] = newParsedStructField(fmt.Sprintf("field%d", i+1), value, i+1)
+	}
+	return fields, nil
+}
+
+// parseJsonSample flattens the sample JSON object up to the given number of layers, nested keys are joined with
+// dots and deeper values are kept as raw JSON. The keys keep the order of the sample.
+func parseJsonSample(layers int, content string) ([]parsedStructField, error) {
+	if layers < 1 {
+		layers = 1
+	}
+	decoder := json.NewDecoder(strings.NewReader(content))
+	decoder.UseNumber()
+	var fields []parsedStructField
+	if err := walkJsonObject(decoder, "", 1, layers, &fields); err != nil {
+		return nil, fmt.Errorf("the sample log is not a valid JSON object: %s", err)
+	}
+	// More does not report a closing delimiter, so the rest of the sample must be empty
+	if _, err := decoder.Token(); err != io.EOF {
+		return nil, fmt.Errorf("the sample log contains data after the JSON object")
+	}
+	if len(fields) == 0 {
+		return nil, fmt.Errorf("the sample JSON object has no fields")
+	}
+	return fields, nil
+}
+
+func walkJsonObject(decoder *json.Decoder, prefix string, depth, layers int, fields *[]parsedStructField) error {
+	token, err := decoder.Token()
+	if err != nil {
+		return err
+	}
+	if delim, ok := token.(json.Delim); !ok || delim != '{' {
+		return fmt.Errorf("expected an object, got %v", token)
+	}
+	for decoder.More() {
+		token, err := decoder.Token()
+		if err != nil {
+			return err
+		}
+		name := prefix + token.(string)
+		var raw json.RawMessage
+		if err := decoder.Decode(&raw); err != nil {
+			return err
+		}
+		raw = bytes.TrimSpace(raw)
+		if len(raw) > 0 && raw[0] == '{' && depth < layers {
+			nested := json.NewDecoder(bytes.NewReader(raw))
+			nested.UseNumber()
+			if err := walkJsonObject(nested, name+".", depth+1, layers, fields); err != nil {
+				return err
+			}
+			continue
+		}
+		value := string(raw)
+		var str string
+		if json.Unmarshal(raw, &str) == nil {
+			value = str
+		}
+		*fields = append(*fields, newParsedStructField(name, value, len(*fields)+1))
+	}
+	_, err = decoder.Token()
+	return err
+}
+
+// compileStructRegex compiles the regex rules of a struct template, the Java style named groups accepted by LTS,
+// e.g. (?<status>\d+), are converted to the Go syntax.
+func compileStructRegex(rules string) (*regexp.Regexp, error) {
+	re, err := regexp.Compile(javaNamedGroupRegexp.ReplaceAllString(rules, "(?P<$1>"))
+	if err != nil {
+		return nil, err
+	}
+	for _, name := range re.SubexpNames() {
+		if name != "" {
+			return re, nil
+		}
+	}
+	return nil, fmt.Errorf("the regular expression must contain at least one named group")
+}
+
+// structRegexNeedsJava reports whether the regex rules use Java features which RE2 does not support, i.e.
+// lookarounds, atomic groups, backreferences and possessive quantifiers. Such rules can only be checked by LTS.
+// Other escaped characters are matched on their own, so e.g. \++ is not taken for a possessive quantifier.
+func structRegexNeedsJava(rules string) bool {
+	for _, match := range javaOnlyRegexp.FindAllStringSubmatch(rules, -1) {
+		if match[1] != "" {
+			return true
+		}
+	}
+	return false
+}
+
+func parseRegexSample(rules, content string) ([]parsedStructField, error) {
+	if rules == "" {
+		return nil, fmt.Errorf("regex rules are required for the regex parse type")
+	}
+	re, err := compileStructRegex(rules)
+	if err != nil {
+		return nil, fmt.Errorf("invalid regex rules: %s", err)
+	}
+	return matchNamedGroups(re, content)
+}
+
+func parseNginxSample(logFormat, content string) ([]parsedStructField, error) {
+	if logFormat == "" {
+		return nil, fmt.Errorf("a log format is required for the nginx parse type")
+	}
+	re, err := nginxLogFormatRegexp(logFormat)
+	if err != nil {
+		return nil, err
+	}
+	fields, err := matchNamedGroups(re, content)
+	if err != nil {
+		return nil, err
+	}
+	variables := nginxLogFormatVariables(nginxLogFormatString(logFormat))
+	for i := range fields {
+		fields[i].Type = nginxVariableType(variables[i])
+	}
+	return fields, nil
+}
+
+// nginxLogFormatFields returns one field per variable of an nginx log_format, typed by the variable.
+func nginxLogFormatFields(logFormat string) ([]parsedStructField, error) {
+	re, err := nginxLogFormatRegexp(logFormat)
+	if err != nil {
+		return nil, err
+	}
+	variables := nginxLogFormatVariables(nginxLogFormatString(logFormat))
+	fields := make([]parsedStructField, 0, len(variables))
+	for i, name := range re.SubexpNames()[1:] {
+		fields = append(fields, parsedStructField{
+			FieldName: name,
+			Type:      nginxVariableType(variables[i]),
+			Index:     i + 1,
+		})
+	}
+	return fields, nil
+}
+
+func nginxVariableType(variable string) string {
+	if fieldType, ok := nginxVariableTypes[variable]; ok {
+		return fieldType
+	}
+	return fieldTypeString
+}
+
+func matchNamedGroups(re *regexp.Regexp, content string) ([]parsedStructField, error) {
+	match := re.FindStringSubmatch(content)
+	if match == nil {
+		return nil, fmt.Errorf("the sample log does not match %s", re.String())
+	}
+	var fields []parsedStructField
+	for i, name := range re.SubexpNames() {
+		if name == "" {
+			continue
+		}
+		fields = append(fields, newParsedStructField(name, match[i], len(fields)+1))
+	}
+	return fields, nil
+}
+
+// nginxLogFormatString returns the format string of an nginx log_format value. Both the bare format and a pasted
+// directive are accepted, e.g. log_format main '$remote_addr - $remote_user ' '"$request"';
+func nginxLogFormatString(logFormat string) string {
+	format := strings.TrimSpace(logFormat)
+	format = strings.TrimSpace(strings.TrimSuffix(nginxDirectiveRegexp.ReplaceAllString(format, ""), ";"))
+	quoted := nginxQuotedRegexp.FindAllStringSubmatch(format, -1)
+	if len(quoted) == 0 {
+		return format
+	}
+	var builder strings.Builder
+	for _, segment := range quoted {
+		builder.WriteString(nginxUnescaper.Replace(segment[1] + segment[2]))
+	}
+	return builder.String()
+}
+
+func nginxLogFormatVariables(format string) []string {
+	locations := nginxVariableRegexp.FindAllStringSubmatchIndex(format, -1)
+	variables := make([]string, len(locations))
+	for i, loc := range locations {
+		if loc[2] >= 0 {
+			variables[i] = format[loc[2]:loc[3]]
+		} else {
+			variables[i] = format[loc[4]:loc[5]]
+		}
+	}
+	return variables
+}
+
+// nginxLogFormatRegexp translates an nginx log_format into an anchored regular expression with one named group
+// per variable. Repeated variables get a numeric suffix which is not the name of another variable.
+func nginxLogFormatRegexp(logFormat string) (*regexp.Regexp, error) {
+	format := nginxLogFormatString(logFormat)
+	locations := nginxVariableRegexp.FindAllStringSubmatchIndex(format, -1)
+	if len(locations) == 0 {
+		return nil, fmt.Errorf("the log format does not contain any variable")
+	}
+	variables := nginxLogFormatVariables(format)
+	var builder strings.Builder
+	builder.WriteString("^")
+	last := 0
+	taken := make(map[string]bool)
+	for _, name := range variables {
+		taken[name] = true
+	}
+	seen := make(map[string]bool)
+	for i, loc := range locations {
+		builder.WriteString(regexp.QuoteMeta(format[last:loc[0]]))
+		name := variables[i]
+		if seen[name] {
+			for n := 2; taken[name]; n++ {
+				name = fmt.Sprintf("%s_%d", variables[i], n)
+			}
+			taken[name] = true
+		}
+		seen[variables[i]] = true
+		if i == len(locations)-1 && loc[1] == len(format) {
+			builder.WriteString("(?P<" + name + ">.*)")
+		} else {
+			builder.WriteString("(?P<" + name + ">.*?)")
+		}
+		last = loc[1]
+	}
+	builder.WriteString(regexp.QuoteMeta(format[last:]))
+	builder.WriteString("$")
+	return regexp.Compile(builder.String())
+}
+
+func newParsedStructField(name, value string, index int) parsedStructField {
+	return parsedStructField{
+		FieldName: name,
+		Type:      inferStructFieldType(value),
+		Content:   value,
+		Index:     index,
+	}
+}
+
+func inferStructFieldType(value string) string {
+	if _, err := strconv.ParseInt(value, 10, 64); err == nil {
+		return fieldTypeLong
+	}
+	if _, err := strconv.ParseFloat(value, 64); err == nil && strings.ContainsAny(value, ".eE") {
+		return fieldTypeFloat
+	}
+	return fieldTypeString
+}
@@ -0,0 +1,232 @@
+package lts
+
+import (
+	"reflect"
+	"testing"
+)
+
+// checkParsedFields parses a sample log and compares the extracted fields, a nil want expects the sample to be
+// rejected.
+func checkParsedFields(t *testing.T, rules structParseRules, content string, want []parsedStructField) {
+	t.Helper()
+	got, err := parseStructSample(rules, content)
+	switch {
+	case want == nil && err == nil:
+		t.Errorf("parsing %q with %+v: expected an error, got %v", content, rules, got)
+	case want != nil && err != nil:
+		t.Errorf("parsing %q with %+v: %s", content, rules, err)
+	case want != nil && !reflect.DeepEqual(got, want):
+		t.Errorf("parsing %q with %+v:\n got %v\nwant %v", content, rules, got, want)
+	}
+}
+
+func TestParseSplitSample(t *testing.T) {
+	rules := structParseRules{ParseType: parseTypeSplit, Tokenizer: "|"}
+	checkParsedFields(t, rules, "GET|200|0.25", []parsedStructField{
+		{FieldName: "field1", Type: fieldTypeString, Content: "GET", Index: 1},
+		{FieldName: "field2", Type: fieldTypeLong, Content: "200", Index: 2},
+		{FieldName: "field3", Type: fieldTypeFloat, Content: "0.25", Index: 3},
+	})
+	checkParsedFields(t, rules, "GET||", []parsedStructField{
+		{FieldName: "field1", Type: fieldTypeString, Content: "GET", Index: 1},
+		{FieldName: "field2", Type: fieldTypeString, Content: "", Index: 2},
+		{FieldName: "field3", Type: fieldTypeString, Content: "", Index: 3},
+	})
+	checkParsedFields(t, structParseRules{ParseType: parseTypeSplit, Tokenizer: " - "}, "a - b", []parsedStructField{
+		{FieldName: "field1", Type: fieldTypeString, Content: "a", Index: 1},
+		{FieldName: "field2", Type: fieldTypeString, Content: "b", Index: 2},
+	})
+	checkParsedFields(t, structParseRules{ParseType: parseTypeSplit}, "GET|200", nil)
+}
+
+func TestParseJsonSample(t *testing.T) {
+	oneLayer := structParseRules{ParseType: parseTypeJson, Layers: 1}
+	checkParsedFields(t, oneLayer, `{"level":"info","code":404,"req":{"path":"/apm"}}`, []parsedStructField{
+		{FieldName: "level", Type: fieldTypeString, Content: "info", Index: 1},
+		{FieldName: "code", Type: fieldTypeLong, Content: "404", Index: 2},
+		{FieldName: "req", Type: fieldTypeString, Content: `{"path":"/apm"}`, Index: 3},
+	})
+	checkParsedFields(t, structParseRules{ParseType: parseTypeJson, Layers: 2},
+		`{"level":"info","req":{"path":"/apm","cost":1.5}}`, []parsedStructField{
+			{FieldName: "level", Type: fieldTypeString, Content: "info", Index: 1},
+			{FieldName: "req.path", Type: fieldTypeString, Content: "/apm", Index: 2},
+			{FieldName: "req.cost", Type: fieldTypeFloat, Content: "1.5", Index: 3},
+		})
+	checkParsedFields(t, oneLayer, " {\"a\":1}\n", []parsedStructField{
+		{FieldName: "a", Type: fieldTypeLong, Content: "1", Index: 1},
+	})
+
+	for _, content := range []string{`[1,2]`, `"a"`, `{}`, `{"a":1}{"b":2}`, `{"a":1}}`, `{"a":1}}}`, `{"a":1}]`,
+		`{"a":1} x`, `{"a":`} {
+		checkParsedFields(t, oneLayer, content, nil)
+	}
+}
+
+func TestParseRegexSample(t *testing.T) {
+	checkParsedFields(t, structParseRules{ParseType: parseTypeRegex, RegexRules: `^(?<method>\w+) (?<status>\d+)$`},
+		"GET 200", []parsedStructField{
+			{FieldName: "method", Type: fieldTypeString, Content: "GET", Index: 1},
+			{FieldName: "status", Type: fieldTypeLong, Content: "200", Index: 2},
+		})
+	checkParsedFields(t, structParseRules{ParseType: parseTypeRegex, RegexRules: `^(\w+) (?P<status>\d+)$`},
+		"GET 200", []parsedStructField{
+			{FieldName: "status", Type: fieldTypeLong, Content: "200", Index: 1},
+		})
+	checkParsedFields(t, structParseRules{ParseType: parseTypeRegex, RegexRules: `^(\w+)$`}, "GET", nil)
+	checkParsedFields(t, structParseRules{ParseType: parseTypeRegex, RegexRules: `^(?<status>\d+)$`}, "GET", nil)
+	checkParsedFields(t, structParseRules{ParseType: parseTypeRegex}, "GET", nil)
+}
+
+func TestParseNginxSample(t *testing.T) {
+	checkParsedFields(t, structParseRules{
+		ParseType: parseTypeNginx,
+		LogFormat: `log_format main "$remote_addr \"$request\" $status";`,
+	}, `10.0.0.1 "GET /apm HTTP/1.1" 404`, []parsedStructField{
+		{FieldName: "remote_addr", Type: fieldTypeString, Content: "10.0.0.1", Index: 1},
+		{FieldName: "request", Type: fieldTypeString, Content: "GET /apm HTTP/1.1", Index: 2},
+		{FieldName: "status", Type: fieldTypeLong, Content: "404", Index: 3},
+	})
+	checkParsedFields(t, structParseRules{
+		ParseType: parseTypeNginx,
+		LogFormat: `'$remote_user $request_time' ' $body_bytes_sent'`,
+	}, "42 0.003 512", []parsedStructField{
+		{FieldName: "remote_user", Type: fieldTypeString, Content: "42", Index: 1},
+		{FieldName: "request_time", Type: fieldTypeFloat, Content: "0.003", Index: 2},
+		{FieldName: "body_bytes_sent", Type: fieldTypeLong, Content: "512", Index: 3},
+	})
+	// repeated variables keep the type of the variable under their suffixed names
+	checkParsedFields(t, structParseRules{ParseType: parseTypeNginx, LogFormat: `$status $status_2 [$status]`},
+		"200 ok [404]", []parsedStructField{
+			{FieldName: "status", Type: fieldTypeLong, Content: "200", Index: 1},
+			{FieldName: "status_2", Type: fieldTypeString, Content: "ok", Index: 2},
+			{FieldName: "status_3", Type: fieldTypeLong, Content: "404", Index: 3},
+		})
+	checkParsedFields(t, structParseRules{ParseType: parseTypeNginx, LogFormat: `$request_time/${request_time}`},
+		"0.1/0.2", []parsedStructField{
+			{FieldName: "request_time", Type: fieldTypeFloat, Content: "0.1", Index: 1},
+			{FieldName: "request_time_2", Type: fieldTypeFloat, Content: "0.2", Index: 2},
+		})
+	checkParsedFields(t, structParseRules{ParseType: parseTypeNginx, LogFormat: `$status $status`}, "200", nil)
+	checkParsedFields(t, structParseRules{ParseType: parseTypeNginx, LogFormat: "plain text"}, "plain text", nil)
+}
+
+func TestParseStructSampleErrors(t *testing.T) {
+	checkParsedFields(t, structParseRules{ParseType: parseTypeSplit, Tokenizer: ","}, "", nil)
+	checkParsedFields(t, structParseRules{ParseType: "xml"}, "<a/>", nil)
+	checkParsedFields(t, structParseRules{}, "GET", nil)
+}
+
+func TestNginxLogFormatString(t *testing.T) {
+	cases := []struct {
+		name      string
+		logFormat string
+		want      string
+	}{
+		{
+			name:      "bare format",
+			logFormat: `$remote_addr - $status`,
+			want:      `$remote_addr - $status`,
+		},
+		{
+			name:      "directive with segments",
+			logFormat: "log_format main '$remote_addr - ' \n '$status';",
+			want:      `$remote_addr - $status`,
+		},
+		{
+			name:      "escaped quotes and backslashes",
+			logFormat: `"\"$request\" \\ $status" '\'$host\''`,
+			want:      `"$request" \ $status'$host'`,
+		},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			if got := nginxLogFormatString(c.logFormat); got != c.want {
+				t.Fatalf("got %q, want %q", got, c.want)
+			}
+		})
+	}
+}
+
+func TestNginxLogFormatFields(t *testing.T) {
+	cases := []struct {
+		name      string
+		logFormat string
+		want      []string
+	}{
+		{
+			name:      "braced variables",
+			logFormat: `${remote_addr}:${remote_port}`,
+			want:      []string{"remote_addr", "remote_port"},
+		},
+		{
+			name:      "repeated variable",
+			logFormat: `$status $status`,
+			want:      []string{"status", "status_2"},
+		},
+		{
+			name:      "repeated variable next to a suffixed one",
+			logFormat: `$status $status_2 $status`,
+			want:      []string{"status", "status_2", "status_3"},
+		},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			fields, err := nginxLogFormatFields(c.logFormat)
+			if err != nil {
+				t.Fatalf("unexpected error: %s", err)
+			}
+			got := make([]string, len(fields))
+			for i, field := range fields {
+				got[i] = field.FieldName
+			}
+			if !reflect.DeepEqual(got, c.want) {
+				t.Fatalf("got %v, want %v", got, c.want)
+			}
+		})
+	}
+}
+
+func TestInferStructFieldType(t *testing.T) {
+	cases := map[string]string{
+		"200":    fieldTypeLong,
+		"-3":     fieldTypeLong,
+		"0.25":   fieldTypeFloat,
+		"1e3":    fieldTypeFloat,
+		"Inf":    fieldTypeString,
+		"GET":    fieldTypeString,
+		"":       fieldTypeString,
+		"10.0.1": fieldTypeString,
+	}
+
+	for value, want := range cases {
+		if got := inferStructFieldType(value); got != want {
+			t.Errorf("inferStructFieldType(%q) = %q, want %q", value, got, want)
+		}
+	}
+}
+
+func TestStructRegexNeedsJava(t *testing.T) {
+	cases := map[string]bool{
+		`^(?<method>\w+) (?<status>\d+)$`: false,
+		`(?<path>/\S*)(?=\s)`:             true,
+		`(?<!\d)(?<code>\d{3})`:           true,
+		`(?<=user=)(?<user>\w+)`:          true,
+		`(?<q>['"])(?<v>.*?)\1`:           true,
+		`(?<v>\w++)`:                      true,
+		`(?>a|ab)(?<c>c)`:                 true,
+		`(?<v>a{2}+)`:                     true,
+		`(?<sum>\d+\++\d+)`:               false,
+		`(?<v>\(?=\d+)`:                   false,
+		`(?<path>C:\\1\\\w+)`:             false,
+		`(?<v>\\++)`:                      true,
+		`(?<v>\?+)`:                       false,
+	}
+
+	for rules, want := range cases {
+		if got := structRegexNeedsJava(rules); got != want {
+			t.Errorf("structRegexNeedsJava(%q) = %t, want %t", rules, got, want)
+		}
+	}
+}